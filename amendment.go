@@ -0,0 +1,197 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReportTitle is the information efdsearch encodes in a report name
+// such as "Periodic Transaction Report for 03/12/2020 (Amendment 1)"
+// Base is the name without the amendment suffix, which is shared by the original report and its amendments
+// Period is the covered period as written, and PeriodStart and PeriodEnd are only set if it could be parsed
+// Amendment is 0 for original reports
+type ReportTitle struct {
+	Base        string    `json:"base"`
+	Period      string    `json:"period,omitempty"`
+	PeriodStart time.Time `json:"periodstart"`
+	PeriodEnd   time.Time `json:"periodend"`
+	Amendment   int       `json:"amendment"`
+}
+
+// AmendmentChain is an original report and its amendments, in order
+// Original is nil if the original report was not among the results the chain was built from
+type AmendmentChain struct {
+	FirstName  string
+	LastName   string
+	Base       string
+	Original   *SearchResult
+	Amendments []SearchResult
+}
+
+// Patterns for the parts of a report name
+var (
+	amendmentPattern  = regexp.MustCompile(`(?i)\s*\(\s*amendment\s*(\d*)\s*\)\s*$`)
+	periodPattern     = regexp.MustCompile(`(?i)^(.*?)\s+for\s+(.+)$`)
+	periodYearPattern = regexp.MustCompile(`(?i)^(?:cy|calendar year)\s*(\d{4})$`)
+)
+
+// ParseReportTitle parses the covered period and amendment number out of a report name
+// Dates in the name are parsed with the default 01/02/2006 layout
+func ParseReportTitle(name string) ReportTitle {
+	return parseReportTitle(name, defaultDateLayout)
+}
+
+// parseReportTitle parses a report name, using dateLayout for periods which are a single date
+// An amendment without a number, "(Amendment)", is taken as the first amendment
+func parseReportTitle(name string, dateLayout string) ReportTitle {
+	var title ReportTitle
+
+	name = strings.Join(strings.Fields(name), " ")
+	title.Base = name
+
+	if match := amendmentPattern.FindStringSubmatchIndex(name); match != nil {
+		title.Base = name[:match[0]]
+		title.Amendment = 1
+		if match[2] != match[3] {
+			title.Amendment, _ = strconv.Atoi(name[match[2]:match[3]])
+		}
+	}
+
+	match := periodPattern.FindStringSubmatch(title.Base)
+	if match == nil {
+		return title
+	}
+
+	title.Period = match[2]
+	if t, err := time.Parse(dateLayout, title.Period); err == nil {
+		title.PeriodStart = t
+		title.PeriodEnd = t
+	} else if year := periodYearPattern.FindStringSubmatch(title.Period); year != nil {
+		y, _ := strconv.Atoi(year[1])
+		title.PeriodStart = time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+		title.PeriodEnd = time.Date(y, time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	return title
+}
+
+// IsAmendment reports whether the search result is an amendment of an earlier report
+func (s SearchResult) IsAmendment() bool {
+	return s.Title.Amendment > 0
+}
+
+// ChainAmendments groups search results into chains of an original report and its amendments
+// Each original report starts its own chain, as filers often submit several reports with the same name on one day
+// An amendment joins the chain of the latest original by the same filer with the same title Base submitted no later
+// than it, and amendments with no such original are grouped by filer and Base with a nil Original
+// Chains are returned in order of their first submission, and amendments are ordered by amendment number and then
+// submission date
+func ChainAmendments(results []SearchResult) []AmendmentChain {
+	var chains []*AmendmentChain
+	originals := make(map[string][]*AmendmentChain)
+	orphans := make(map[string]*AmendmentChain)
+
+	for _, result := range results {
+		if result.IsAmendment() {
+			continue
+		}
+
+		result := result
+		chain := &AmendmentChain{FirstName: result.FirstName, LastName: result.LastName, Base: result.Title.Base,
+			Original: &result}
+		chains = append(chains, chain)
+
+		key := chainKey(result)
+		originals[key] = append(originals[key], chain)
+	}
+
+	for _, result := range results {
+		if !result.IsAmendment() {
+			continue
+		}
+
+		key := chainKey(result)
+
+		var chain *AmendmentChain
+		for _, candidate := range originals[key] {
+			if candidate.Original.DateSubmitted.After(result.DateSubmitted) {
+				continue
+			}
+
+			if chain == nil || !candidate.Original.DateSubmitted.Before(chain.Original.DateSubmitted) {
+				chain = candidate
+			}
+		}
+
+		if chain == nil {
+			chain = orphans[key]
+		}
+
+		if chain == nil {
+			chain = &AmendmentChain{FirstName: result.FirstName, LastName: result.LastName, Base: result.Title.Base}
+			orphans[key] = chain
+			chains = append(chains, chain)
+		}
+
+		chain.Amendments = append(chain.Amendments, result)
+	}
+
+	out := make([]AmendmentChain, len(chains))
+	for i, chain := range chains {
+		sort.SliceStable(chain.Amendments, func(a, b int) bool {
+			x, y := chain.Amendments[a], chain.Amendments[b]
+			if x.Title.Amendment != y.Title.Amendment {
+				return x.Title.Amendment < y.Title.Amendment
+			}
+
+			return x.DateSubmitted.Before(y.DateSubmitted)
+		})
+
+		out[i] = *chain
+	}
+
+	sort.SliceStable(out, func(a, b int) bool {
+		return out[a].first().DateSubmitted.Before(out[b].first().DateSubmitted)
+	})
+
+	return out
+}
+
+// Versions returns every report in the chain in order, starting with the original if it is known
+func (a AmendmentChain) Versions() []SearchResult {
+	var versions []SearchResult
+
+	if a.Original != nil {
+		versions = append(versions, *a.Original)
+	}
+
+	return append(versions, a.Amendments...)
+}
+
+// Latest returns the most recent version of the report
+func (a AmendmentChain) Latest() SearchResult {
+	if len(a.Amendments) > 0 {
+		return a.Amendments[len(a.Amendments)-1]
+	}
+
+	return *a.Original
+}
+
+// chainKey identifies the filer and title Base shared by an original report and its amendments
+func chainKey(result SearchResult) string {
+	return strings.ToLower(result.FirstName) + "\x00" + strings.ToLower(result.LastName) + "\x00" +
+		strings.ToLower(result.Title.Base)
+}
+
+// first returns the earliest version of the report
+func (a AmendmentChain) first() SearchResult {
+	if a.Original != nil {
+		return *a.Original
+	}
+
+	return a.Amendments[0]
+}
@@ -0,0 +1,120 @@
+package efd_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// titled builds a search result for Jane Doe with the provided report name and submission date
+func titled(id, name string, submitted time.Time) efd.SearchResult {
+	return efd.SearchResult{
+		FirstName:     "Jane",
+		LastName:      "Doe",
+		ReportID:      id,
+		ReportName:    name,
+		Title:         efd.ParseReportTitle(name),
+		DateSubmitted: submitted,
+	}
+}
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2020, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseReportTitle(t *testing.T) {
+	title := efd.ParseReportTitle("Periodic Transaction Report for 03/12/2020 (Amendment 2)")
+	if title.Base != "Periodic Transaction Report for 03/12/2020" || title.Amendment != 2 ||
+		!title.PeriodStart.Equal(day(time.March, 12)) {
+		t.Errorf("unexpected title %+v", title)
+	}
+
+	title = efd.ParseReportTitle("Annual Report for CY 2019 (Amendment)")
+	if title.Amendment != 1 || !title.PeriodEnd.Equal(time.Date(2019, time.December, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected title %+v", title)
+	}
+}
+
+func TestChainAmendmentsKeepsSameDayReportsApart(t *testing.T) {
+	name := "Periodic Transaction Report for 03/12/2020"
+	chains := efd.ChainAmendments([]efd.SearchResult{
+		titled("a", name, day(time.March, 12)),
+		titled("b", name, day(time.March, 12)),
+	})
+
+	if len(chains) != 2 {
+		t.Fatalf("got %d chains, want 2", len(chains))
+	}
+
+	for _, chain := range chains {
+		if chain.Original == nil || len(chain.Amendments) != 0 {
+			t.Errorf("chain %+v should be a lone original", chain)
+		}
+	}
+}
+
+func TestChainAmendmentsAttachesToLatestEarlierOriginal(t *testing.T) {
+	name := "Annual Report for CY 2019"
+	chains := efd.ChainAmendments([]efd.SearchResult{
+		titled("late-amendment", name+" (Amendment 1)", day(time.February, 15)),
+		titled("first", name, day(time.January, 10)),
+		titled("early-amendment", name+" (Amendment 1)", day(time.January, 20)),
+		titled("second", name, day(time.February, 10)),
+	})
+
+	if len(chains) != 2 {
+		t.Fatalf("got %d chains, want 2", len(chains))
+	}
+
+	want := map[string]string{"first": "early-amendment", "second": "late-amendment"}
+	for _, chain := range chains {
+		if len(chain.Amendments) != 1 || chain.Latest().ReportID != want[chain.Original.ReportID] {
+			t.Errorf("chain of %s has amendments %+v, want %s", chain.Original.ReportID, chain.Amendments,
+				want[chain.Original.ReportID])
+		}
+	}
+}
+
+func TestChainAmendmentsGroupsOrphanedAmendments(t *testing.T) {
+	name := "Periodic Transaction Report for 03/12/2020"
+	chains := efd.ChainAmendments([]efd.SearchResult{
+		titled("second", name+" (Amendment 2)", day(time.April, 2)),
+		titled("first", name+" (Amendment 1)", day(time.March, 20)),
+	})
+
+	if len(chains) != 1 || chains[0].Original != nil {
+		t.Fatalf("got chains %+v, want one chain without an original", chains)
+	}
+
+	versions := chains[0].Versions()
+	if len(versions) != 2 || versions[0].ReportID != "first" || chains[0].Latest().ReportID != "second" {
+		t.Errorf("got versions %+v", versions)
+	}
+}
+
+func TestChainAmendmentsFromSearch(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	results, err := server.EFDClient().Search(context.Background(), efd.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	for _, chain := range efd.ChainAmendments(results) {
+		if chain.Original.ReportID != efdtest.PTRFixtureID {
+			if len(chain.Amendments) != 0 {
+				t.Errorf("%s has unexpected amendments", chain.Original.ReportID)
+			}
+
+			continue
+		}
+
+		if len(chain.Amendments) != 1 || chain.Latest().ReportID != efdtest.PTRAmendmentFixtureID {
+			t.Errorf("PTR chain has amendments %+v", chain.Amendments)
+		}
+	}
+}
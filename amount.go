@@ -0,0 +1,177 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AmountBand indicates which statutory STOCK Act range a transaction amount was reported in
+type AmountBand int
+
+// Enumeration of the amount ranges used on PTRs and Annual reports
+// UnknownAmountBand is used for exact amounts and values which do not match a statutory range
+const (
+	UnknownAmountBand AmountBand = iota
+	Amount1KTo15K
+	Amount15KTo50K
+	Amount50KTo100K
+	Amount100KTo250K
+	Amount250KTo500K
+	Amount500KTo1M
+	Amount1MTo5M
+	Amount5MTo25M
+	Amount25MTo50M
+	AmountOver50M
+	AmountOver1M
+)
+
+// amountBandCodes are the stable string codes used when marshalling each AmountBand
+var amountBandCodes = []string{"unknown", "1k_15k", "15k_50k", "50k_100k", "100k_250k", "250k_500k", "500k_1m",
+	"1m_5m", "5m_25m", "25m_50m", "over_50m", "over_1m"}
+
+// AmountRange is a parsed amount, with bounds in integer cents
+// Max is 0 and OpenEnded is set for ranges with no upper bound such as "Over $50,000,000"
+type AmountRange struct {
+	Min       int64
+	Max       int64
+	OpenEnded bool
+	Band      AmountBand
+}
+
+// amountBands lists the bounds in whole dollars of each statutory range
+var amountBands = []struct {
+	min       int64
+	max       int64
+	openEnded bool
+	band      AmountBand
+}{
+	{1001, 15000, false, Amount1KTo15K},
+	{15001, 50000, false, Amount15KTo50K},
+	{50001, 100000, false, Amount50KTo100K},
+	{100001, 250000, false, Amount100KTo250K},
+	{250001, 500000, false, Amount250KTo500K},
+	{500001, 1000000, false, Amount500KTo1M},
+	{1000001, 5000000, false, Amount1MTo5M},
+	{5000001, 25000000, false, Amount5MTo25M},
+	{25000001, 50000000, false, Amount25MTo50M},
+	{50000001, 0, true, AmountOver50M},
+	{1000001, 0, true, AmountOver1M},
+}
+
+// dollarPattern matches a dollar value such as "$1,001" or "$2,000.00"
+var dollarPattern = regexp.MustCompile(`\$\s*(\d[\d,]*)(?:\.(\d{1,2}))?`)
+
+// String returns the range as it is written on reports
+func (b AmountBand) String() string {
+	switch b {
+	case Amount1KTo15K:
+		return "$1,001 - $15,000"
+	case Amount15KTo50K:
+		return "$15,001 - $50,000"
+	case Amount50KTo100K:
+		return "$50,001 - $100,000"
+	case Amount100KTo250K:
+		return "$100,001 - $250,000"
+	case Amount250KTo500K:
+		return "$250,001 - $500,000"
+	case Amount500KTo1M:
+		return "$500,001 - $1,000,000"
+	case Amount1MTo5M:
+		return "$1,000,001 - $5,000,000"
+	case Amount5MTo25M:
+		return "$5,000,001 - $25,000,000"
+	case Amount25MTo50M:
+		return "$25,000,001 - $50,000,000"
+	case AmountOver50M:
+		return "Over $50,000,000"
+	case AmountOver1M:
+		return "Over $1,000,000"
+	}
+
+	return "Unknown"
+}
+
+// MarshalText implements text marshalling to the stable code of the AmountBand, such as "1k_15k"
+func (b AmountBand) MarshalText() ([]byte, error) {
+	return []byte(kindCode(amountBandCodes, int(b))), nil
+}
+
+// UnmarshalText implements text unmarshalling from the stable code of an AmountBand
+func (b *AmountBand) UnmarshalText(text []byte) error {
+	i, err := kindFromCode(amountBandCodes, string(text))
+	*b = AmountBand(i)
+	return err
+}
+
+// ParseAmount parses an amount such as "$1,001 - $15,000", "Over $50,000,000", or "$2,000.00" into a range
+// An open ended amount, written as "Over $50,000,000" or "$50,000,000+", is taken to start one dollar above the
+// stated value, matching the statutory ranges
+// Values with no dollar amount return an error wrapping ErrInvalidAmount
+func ParseAmount(amount string) (AmountRange, error) {
+	var r AmountRange
+
+	text := strings.TrimSpace(html.UnescapeString(amount))
+	matches := dollarPattern.FindAllStringSubmatch(text, -1)
+
+	var values []int64
+	for _, match := range matches {
+		cents, err := parseCents(match[1], match[2])
+		if err != nil {
+			return r, fmt.Errorf("%w: %q: %v", ErrInvalidAmount, amount, err)
+		}
+
+		values = append(values, cents)
+	}
+
+	lower := strings.ToLower(text)
+	switch {
+	case len(values) == 1 && (strings.HasPrefix(lower, "over") || strings.Contains(lower, " over ") ||
+		strings.HasSuffix(lower, "+")):
+		r.Min = values[0] + 100
+		r.OpenEnded = true
+	case len(values) == 1:
+		r.Min = values[0]
+		r.Max = values[0]
+	case len(values) == 2 && values[0] <= values[1]:
+		r.Min = values[0]
+		r.Max = values[1]
+	default:
+		return r, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	for _, band := range amountBands {
+		if r.Min == band.min*100 && r.Max == band.max*100 && r.OpenEnded == band.openEnded {
+			r.Band = band.band
+			break
+		}
+	}
+
+	return r, nil
+}
+
+// parseCents converts whole dollars with optional thousands separators and a fractional part into cents
+func parseCents(dollars, fraction string) (int64, error) {
+	whole, err := strconv.ParseInt(strings.Replace(dollars, ",", "", -1), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	cents := whole * 100
+	if fraction != "" {
+		part, err := strconv.ParseInt(fraction, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(fraction) == 1 {
+			part *= 10
+		}
+		cents += part
+	}
+
+	return cents, nil
+}
@@ -0,0 +1,53 @@
+package efd_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		want   efd.AmountRange
+	}{
+		{"$1,001 - $15,000", efd.AmountRange{Min: 100100, Max: 1500000, Band: efd.Amount1KTo15K}},
+		{"Over $50,000,000", efd.AmountRange{Min: 5000000100, OpenEnded: true, Band: efd.AmountOver50M}},
+		{"$50,000,000+", efd.AmountRange{Min: 5000000100, OpenEnded: true, Band: efd.AmountOver50M}},
+		{"Spouse/DC Over $1,000,000", efd.AmountRange{Min: 100000100, OpenEnded: true, Band: efd.AmountOver1M}},
+		{"$2,000.50", efd.AmountRange{Min: 200050, Max: 200050}},
+	}
+
+	for _, tt := range tests {
+		got, err := efd.ParseAmount(tt.amount)
+		if err != nil {
+			t.Errorf("ParseAmount(%q): %v", tt.amount, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q) = %+v, want %+v", tt.amount, got, tt.want)
+		}
+	}
+
+	if _, err := efd.ParseAmount("None disclosed"); err == nil {
+		t.Error("ParseAmount accepted an amount with no dollar value")
+	}
+}
+
+func TestAmountBandJSON(t *testing.T) {
+	out, err := json.Marshal(efd.Amount15KTo50K)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != `"15k_50k"` {
+		t.Errorf("got %s, want \"15k_50k\"", out)
+	}
+
+	var band efd.AmountBand
+	if err := json.Unmarshal(out, &band); err != nil || band != efd.Amount15KTo50K {
+		t.Errorf("round trip gave %v, %v", band, err)
+	}
+}
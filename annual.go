@@ -0,0 +1,356 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// tableRow maps the field names of a table's columns to the cells of one row
+type tableRow map[string]*goquery.Selection
+
+// annualSection describes how to parse one of the generic tables of a digital Annual report
+// headers maps normalized header labels to field names, an empty field name ignores the column
+// parse fills in the report from a row, returning false if the row is malformed
+type annualSection struct {
+	prefix  string
+	headers map[string]string
+	parse   func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool
+}
+
+// tickerPattern matches the ticker listed under an asset name
+var tickerPattern = regexp.MustCompile(`Ticker:\s*(\S+)`)
+
+// annualSections lists every part of a digital Annual report other than the Part 4 transactions
+var annualSections = []annualSection{
+	{
+		prefix: "Part 1.",
+		headers: map[string]string{
+			"#": "", "date": "date", "activity": "activity", "amount": "amount", "paid by": "paidby", "paid to": "paidto",
+		},
+		parse: func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool {
+			var contribution CharitableContribution
+			var ok bool
+
+			if contribution.Date, ok = c.cellDate(row["date"]); !ok {
+				return false
+			}
+
+			contribution.Activity = c.cellText(row["activity"])
+			contribution.Amount = c.cellText(row["amount"])
+			contribution.PaidBy = c.cellText(row["paidby"])
+			contribution.PaidTo = c.cellText(row["paidto"])
+			report.CharitableContributions = append(report.CharitableContributions, contribution)
+
+			return true
+		},
+	},
+	{
+		prefix: "Part 2.",
+		headers: map[string]string{
+			"#": "", "who was paid": "recipient", "type": "type", "who paid": "payer", "amount paid": "amount", "amount": "amount",
+		},
+		parse: func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool {
+			report.EarnedIncome = append(report.EarnedIncome, EarnedIncome{
+				Recipient: c.cellText(row["recipient"]),
+				Type:      c.cellText(row["type"]),
+				Payer:     c.cellText(row["payer"]),
+				Amount:    c.cellText(row["amount"]),
+			})
+
+			return true
+		},
+	},
+	{
+		prefix: "Part 3.",
+		headers: map[string]string{
+			"#": "", "asset": "asset", "asset type": "assettype", "owner": "owner", "value": "value",
+			"income type": "incometype", "income": "income",
+		},
+		parse: func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool {
+			var asset Asset
+
+			cell := row["asset"]
+			if cell == nil {
+				return false
+			}
+
+			// Additional details such as the ticker are listed in a muted div under the name
+			details := cell.Find("div.text-muted")
+			asset.Description = c.cellText(details)
+			asset.Name = c.cellText(cell.Clone().Find("div.text-muted").Remove().End())
+			if asset.Name == "" {
+				return false
+			}
+
+			if match := tickerPattern.FindStringSubmatch(asset.Description); match != nil {
+				asset.Ticker = match[1]
+			}
+
+			asset.AssetType = c.cellText(row["assettype"])
+			asset.Owner = c.cellText(row["owner"])
+			asset.Value = c.cellText(row["value"])
+			asset.IncomeType = c.cellText(row["incometype"])
+			asset.Income = c.cellText(row["income"])
+			report.Assets = append(report.Assets, asset)
+
+			return true
+		},
+	},
+	{
+		prefix: "Part 5.",
+		headers: map[string]string{
+			"#": "", "date": "date", "recipient": "recipient", "gift": "gift", "value": "value", "from": "from",
+		},
+		parse: func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool {
+			var gift Gift
+			var ok bool
+
+			if gift.Date, ok = c.cellDate(row["date"]); !ok {
+				return false
+			}
+
+			gift.Recipient = c.cellText(row["recipient"])
+			gift.Description = c.cellText(row["gift"])
+			gift.Value = c.cellText(row["value"])
+			gift.Source = c.cellText(row["from"])
+			report.Gifts = append(report.Gifts, gift)
+
+			return true
+		},
+	},
+	{
+		prefix: "Part 6.",
+		headers: map[string]string{
+			"#": "", "date(s)": "dates", "dates": "dates", "traveler(s)": "travelers", "travelers": "travelers",
+			"itinerary": "itinerary", "purpose": "purpose", "reimbursed by": "reimbursedby",
+		},
+		parse: func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool {
+			var travel TravelReimbursement
+			var err error
+
+			// Dates are either a single day or a range in the form "start - end"
+			dates := strings.SplitN(c.cellText(row["dates"]), " - ", 2)
+			travel.StartDate, err = time.Parse(c.dateLayout, strings.TrimSpace(dates[0]))
+			if err != nil {
+				return false
+			}
+
+			travel.EndDate = travel.StartDate
+			if len(dates) == 2 {
+				travel.EndDate, err = time.Parse(c.dateLayout, strings.TrimSpace(dates[1]))
+				if err != nil {
+					return false
+				}
+			}
+
+			travel.Travelers = c.cellText(row["travelers"])
+			travel.Itinerary = c.cellText(row["itinerary"])
+			travel.Purpose = c.cellText(row["purpose"])
+			travel.ReimbursedBy = c.cellText(row["reimbursedby"])
+			report.Travel = append(report.Travel, travel)
+
+			return true
+		},
+	},
+	{
+		prefix: "Part 7.",
+		headers: map[string]string{
+			"#": "", "year incurred": "year", "debtor": "debtor", "type": "type", "points": "points", "term": "term",
+			"rate": "rate", "amount": "amount", "creditor": "creditor",
+		},
+		parse: func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool {
+			report.Liabilities = append(report.Liabilities, Liability{
+				YearIncurred: c.cellText(row["year"]),
+				Debtor:       c.cellText(row["debtor"]),
+				Type:         c.cellText(row["type"]),
+				Points:       c.cellText(row["points"]),
+				Term:         c.cellText(row["term"]),
+				Rate:         c.cellText(row["rate"]),
+				Amount:       c.cellText(row["amount"]),
+				Creditor:     c.cellText(row["creditor"]),
+			})
+
+			return true
+		},
+	},
+	{
+		prefix: "Part 8.",
+		headers: map[string]string{
+			"#": "", "position dates": "dates", "position held": "position", "position": "position", "entity": "entity",
+			"entity type": "entitytype", "comment": "comment", "comments": "comment",
+		},
+		parse: func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool {
+			report.Positions = append(report.Positions, Position{
+				Dates:      c.cellText(row["dates"]),
+				Position:   c.cellText(row["position"]),
+				Entity:     c.cellText(row["entity"]),
+				EntityType: c.cellText(row["entitytype"]),
+				Comment:    c.cellText(row["comment"]),
+			})
+
+			return true
+		},
+	},
+	{
+		prefix: "Part 9.",
+		headers: map[string]string{
+			"#": "", "date": "date", "parties involved": "parties", "type": "type", "status and terms": "terms",
+		},
+		parse: func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool {
+			report.Agreements = append(report.Agreements, Agreement{
+				Date:    c.cellText(row["date"]),
+				Parties: c.cellText(row["parties"]),
+				Type:    c.cellText(row["type"]),
+				Terms:   c.cellText(row["terms"]),
+			})
+
+			return true
+		},
+	},
+	{
+		prefix: "Part 10.",
+		headers: map[string]string{
+			"#": "", "source (name and address)": "source", "source": "source",
+			"brief description of duties": "duties", "duties": "duties",
+		},
+		parse: func(c *EFDClient, report *AnnualDisclosure, row tableRow) bool {
+			report.Compensation = append(report.Compensation, Compensation{
+				Source: c.cellText(row["source"]),
+				Duties: c.cellText(row["duties"]),
+			})
+
+			return true
+		},
+	},
+}
+
+// parseAnnualDocument parses every part of a digital Annual report
+// Skipped rows and unrecognised header labels are recorded in diag
+func (c *EFDClient) parseAnnualDocument(ctx context.Context, doc *goquery.Document, diag *ParseReport) (AnnualDisclosure, error) {
+	var report AnnualDisclosure
+	var err error
+
+	hdrs := doc.Find("section.card div.card-body h3.h4")
+	hdrs.EachWithBreak(func(i int, s *goquery.Selection) bool {
+		title, _ := c.removeHTMLSelection(s)
+		section := s.Parent().Parent()
+		tables := section.Find("div.table-responsive table.table")
+
+		tables.EachWithBreak(func(j int, table *goquery.Selection) bool {
+			switch {
+			case strings.HasPrefix(title, "Part 4a."):
+				// Part 4a PTR table rows have 9 elements each
+				// whitespace, Transaction #, Transaction Date, Owner, Ticker, Asset Name, Transaction Type, Amount, and Comment
+				var transactions []Transaction
+				transactions, err = c.parseTransactionTable(ctx, title, table, diag)
+				report.PTRTransactions = append(report.PTRTransactions, transactions...)
+			case strings.HasPrefix(title, "Part 4b."):
+				// Part 4b Transactions have 9 elements each
+				// whitespace, Transaction #, Owner, Ticker, Asset Name, Transaction Type, Transaction Date, Amount, and Comment
+				var transactions []Transaction
+				transactions, err = c.parseTransactionTable(ctx, title, table, diag)
+				report.Transactions = append(report.Transactions, transactions...)
+			default:
+				for _, sec := range annualSections {
+					if strings.HasPrefix(title, sec.prefix) {
+						err = c.parseAnnualSection(ctx, title, table, sec, &report, diag)
+						break
+					}
+				}
+			}
+
+			return err == nil
+		})
+
+		return err == nil
+	})
+
+	return report, err
+}
+
+// allTransactions returns the Part 4a and Part 4b transactions together
+func (r AnnualDisclosure) allTransactions() []Transaction {
+	var transactions []Transaction
+
+	transactions = append(transactions, r.PTRTransactions...)
+	transactions = append(transactions, r.Transactions...)
+
+	return transactions
+}
+
+// parseAnnualSection parses the rows of one generic Annual report table into the report
+// Rows which do not match the header or fail to parse are dropped and recorded in diag
+func (c *EFDClient) parseAnnualSection(ctx context.Context, title string, table *goquery.Selection,
+	sec annualSection, report *AnnualDisclosure, diag *ParseReport) error {
+	var fields []string
+
+	table.Find("thead tr").First().ChildrenFiltered("th, td").Each(func(i int, th *goquery.Selection) {
+		label := strings.Join(strings.Fields(th.Text()), " ")
+
+		field, ok := sec.headers[normalizeHeader(label)]
+		if !ok && label != "" {
+			diag.UnknownColumns = append(diag.UnknownColumns, title+": "+label)
+		}
+
+		fields = append(fields, field)
+	})
+
+	table.Find("tbody tr").EachWithBreak(func(i int, tr *goquery.Selection) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		tds := tr.ChildrenFiltered("td")
+		if tds.Length() != len(fields) {
+			diag.skipSelection(title, i, tr, fmt.Sprintf("row has %d cells, expected %d", tds.Length(), len(fields)))
+			return true
+		}
+
+		row := make(tableRow)
+		tds.Each(func(j int, td *goquery.Selection) {
+			if fields[j] != "" {
+				row[fields[j]] = td
+			}
+		})
+
+		if !sec.parse(c, report, row) {
+			diag.skipSelection(title, i, tr, "could not parse row")
+		}
+
+		return true
+	})
+
+	return ctx.Err()
+}
+
+// cellText returns the text of a table cell with tags removed, entities decoded, and whitespace collapsed
+// A nil or empty selection returns an empty string
+func (c *EFDClient) cellText(s *goquery.Selection) string {
+	if s == nil || s.Length() == 0 {
+		return ""
+	}
+
+	text, err := c.removeHTMLSelection(s)
+	if err != nil {
+		return ""
+	}
+
+	return html.UnescapeString(text)
+}
+
+// cellDate parses a table cell as a date in the client date layout
+func (c *EFDClient) cellDate(s *goquery.Selection) (time.Time, bool) {
+	t, err := time.Parse(c.dateLayout, c.cellText(s))
+	if err != nil {
+		return t, false
+	}
+
+	return t, true
+}
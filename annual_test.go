@@ -0,0 +1,58 @@
+package efd_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+func TestAnnualTransactionsFromServer(t *testing.T) {
+	parsed, err := fetchOnly(t, fixture(t, efdtest.AnnualFixtureID))
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	annual := parsed.Annual
+	if len(annual.Assets) == 0 || len(annual.Liabilities) == 0 {
+		t.Errorf("annual parts missing: %+v", annual)
+	}
+
+	if want := len(annual.PTRTransactions) + len(annual.Transactions); want == 0 || len(parsed.Transactions) != want {
+		t.Errorf("got %d transactions, want the %d from Parts 4a and 4b", len(parsed.Transactions), want)
+	}
+
+	if !parsed.Diagnostics.Empty() {
+		t.Errorf("unexpected diagnostics %+v", parsed.Diagnostics)
+	}
+}
+
+func TestAnnualReportJSON(t *testing.T) {
+	report := fixture(t, efdtest.AnnualFixtureID)
+	parsed, err := fetchOnly(t, report)
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	out, err := efd.ReportToJson(efd.SearchResult{ReportFormat: efd.AnnualFormat, ReportID: report.ID}, parsed)
+	if err != nil {
+		t.Fatalf("ReportToJson: %v", err)
+	}
+
+	var decoded struct {
+		Transactions []efd.Transaction     `json:"transactions"`
+		Annual       *efd.AnnualDisclosure `json:"annual"`
+	}
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Transactions) == 0 || len(decoded.Transactions) != len(parsed.Transactions) {
+		t.Errorf("got %d top level transactions, want %d", len(decoded.Transactions), len(parsed.Transactions))
+	}
+
+	if decoded.Annual == nil || len(decoded.Annual.Assets) != len(parsed.Annual.Assets) {
+		t.Errorf("annual parts missing from json")
+	}
+}
@@ -0,0 +1,90 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// ArchiveRecord describes a raw response saved to the archive Sink
+// It is stored as json alongside the body, under the same name with a .meta.json suffix
+type ArchiveRecord struct {
+	Name        string    `json:"name"`
+	ReportID    string    `json:"reportid,omitempty"`
+	URL         string    `json:"url"`
+	StatusCode  int       `json:"statuscode"`
+	ContentType string    `json:"contenttype"`
+	FetchedAt   time.Time `json:"fetchedat"`
+	SHA256      string    `json:"sha256"`
+}
+
+// archiveTimeLayout formats fetch times in archive names so they sort chronologically
+const archiveTimeLayout = "20060102T150405.000000000Z"
+
+// WithArchive saves the raw body of every search page and report view to sink before it is parsed
+// Report views are stored as reports/<ReportID>/<fetch time>-<hash>.html and search pages as
+// search/<fetch time>-<hash>.json, each with an ArchiveRecord alongside
+// A failure to archive fails the request with an error wrapping ErrArchiveFailed
+func WithArchive(sink Sink) ClientOption {
+	return func(o *clientOptions) {
+		o.archive = sink
+	}
+}
+
+// archiveResponse saves a buffered response body to the archive sink, if one is configured
+// reportID is empty for search pages
+func (c *EFDClient) archiveResponse(ctx context.Context, reportID string, resp *http.Response, body []byte) error {
+	if c.opts.archive == nil {
+		return nil
+	}
+
+	sum := sha256.Sum256(body)
+
+	record := ArchiveRecord{
+		ReportID:    reportID,
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("content-type"),
+		FetchedAt:   time.Now().UTC(),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}
+
+	if resp.Request != nil && resp.Request.URL != nil {
+		record.URL = resp.Request.URL.String()
+	}
+
+	ext := ".html"
+	if strings.HasPrefix(record.ContentType, "application/json") {
+		ext = ".json"
+	}
+
+	dir := "search"
+	if reportID != "" {
+		dir = path.Join("reports", reportID)
+	}
+
+	record.Name = path.Join(dir, record.FetchedAt.Format(archiveTimeLayout)+"-"+record.SHA256[:16]+ext)
+
+	meta, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrArchiveFailed, err)
+	}
+
+	err = c.opts.archive.Put(ctx, record.Name, body)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrArchiveFailed, record.Name, err)
+	}
+
+	err = c.opts.archive.Put(ctx, record.Name+".meta.json", meta)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrArchiveFailed, record.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package efd_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// failingSink is a Sink whose Put always fails
+type failingSink struct {
+	*memSink
+}
+
+func (s failingSink) Put(ctx context.Context, name string, body []byte) error {
+	return errors.New("disk full")
+}
+
+func TestArchiveStoresResponses(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	sink := newMemSink()
+	authedPTR(t, server.EFDClient(efd.WithArchive(sink)))
+
+	var search, report []string
+	for name := range sink.blobs {
+		switch {
+		case strings.HasSuffix(name, ".meta.json"):
+		case strings.HasPrefix(name, "search/") && strings.HasSuffix(name, ".json"):
+			search = append(search, name)
+		case strings.HasPrefix(name, "reports/"+efdtest.PTRFixtureID+"/") && strings.HasSuffix(name, ".html"):
+			report = append(report, name)
+		default:
+			t.Errorf("unexpected archive entry %s", name)
+		}
+	}
+
+	if len(search) != 1 || len(report) != 1 {
+		t.Fatalf("got search pages %v and reports %v, want one of each", search, report)
+	}
+
+	for _, name := range append(search, report...) {
+		meta, err := sink.Get(context.Background(), name+".meta.json")
+		if err != nil {
+			t.Fatalf("no record for %s: %v", name, err)
+		}
+
+		var record efd.ArchiveRecord
+		if err := json.Unmarshal(meta, &record); err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := sink.Get(context.Background(), name)
+		sum := sha256.Sum256(body)
+		if record.Name != name || record.SHA256 != hex.EncodeToString(sum[:]) || record.StatusCode != 200 ||
+			!strings.HasPrefix(record.URL, server.URL) {
+			t.Errorf("unexpected record for %s: %+v", name, record)
+		}
+	}
+
+	meta, _ := sink.Get(context.Background(), report[0]+".meta.json")
+
+	var record efd.ArchiveRecord
+	json.Unmarshal(meta, &record)
+	ptrPath := fixture(t, efdtest.PTRFixtureID).Path()
+	if record.ReportID != efdtest.PTRFixtureID || !strings.HasSuffix(record.URL, ptrPath) {
+		t.Errorf("unexpected report record %+v", record)
+	}
+}
+
+func TestArchiveFailureFailsRequest(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	client := server.EFDClient(efd.WithArchive(failingSink{newMemSink()}))
+
+	_, err := client.Search(context.Background(), efd.SearchQuery{})
+	if !errors.Is(err, efd.ErrArchiveFailed) {
+		t.Errorf("got error %v, want ErrArchiveFailed", err)
+	}
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/Individual-1/go-efd"
@@ -17,16 +20,30 @@ func main() {
 	startTime, _ := time.Parse(dateLayout, "01/01/2020")
 	endTime, _ := time.Parse(dateLayout, "12/31/2020")
 
+	// Cancel any in-flight searches or report fetches when we are asked to stop
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		cancel()
+	}()
+
 	// In order to interact with the EFDSearch backend, we need to create a client
 	// This client will manage the necessary authorization and cookies to hit each endpoint
 	c := efd.CreateEFDClient("", "")
 
-	// Here we call the SearchReportData client with a FilerType of Senator, and search for the
-	// PeriodicTransactionReport and Annual report types
+	// Here we call Search with a FilerType of Senator, and search for the Annual report type
 	// This method will return an array of SearchResult objects, which contain details about each line item
 	// in the search results
-	searchResults, err := c.SearchReportData("", "", filerType, "",
-		[]efd.ReportType{efd.AnnualReport}, startTime, endTime)
+	searchResults, err := c.Search(ctx, efd.SearchQuery{
+		FilerTypes:  filerType,
+		ReportTypes: []efd.ReportType{efd.AnnualReport},
+		StartTime:   startTime,
+		EndTime:     endTime,
+	})
 	if err != nil {
 		return
 	}
@@ -36,38 +53,43 @@ func main() {
 		cwd = "./"
 	}
 
-	// Now that we have the array of search results, we iterate over them and act upon them
+	// In this example, we are generating a path based on the date the report was uploaded and saving our
+	// output json to that path if it does not already exist
+	var pending []efd.SearchResult
 	for _, result := range searchResults {
+		if !fileExists(jsonPath(cwd, result)) {
+			pending = append(pending, result)
+		}
+	}
 
-		// In this example, we are generating a path based on the date the report was uploaded and saving our
-		// output json to that path if it does not already exist
-		jsonpath := filepath.Join(cwd, "data", fmt.Sprintf("%s.json", result.GenPTRSearchResultPath()))
-		if !fileExists(jsonpath) {
-
-			// HandleResult is a wrapper around individual result handlers, and will select the most appropriate one
-			// to use based on the ReportFormat parsed out of the search objects
-			// Individual handlers can be called too if you want to override this behavior
-			parsedReport, err := c.HandleResult(result)
-			if err != nil {
-				fmt.Println(err)
-				continue
-			}
-
-			//
-			js, err := efd.ReportToJson(result, parsedReport)
-			if err != nil {
-				fmt.Println(err)
-				continue
-			}
-
-			os.MkdirAll(filepath.Dir(jsonpath), os.ModePerm)
-			ioutil.WriteFile(jsonpath, js, 0644)
+	// FetchAll calls HandleResult for each result using a pool of workers sharing our session
+	// HandleResult is a wrapper around individual result handlers, and will select the most appropriate one
+	// to use based on the ReportFormat parsed out of the search objects
+	// Results are returned as each report completes, with any error specific to that report
+	for fetched := range c.FetchAll(ctx, pending, efd.FetchOptions{Workers: 4}) {
+		if fetched.Err != nil {
+			fmt.Println(fetched.Err)
+			continue
 		}
+
+		js, err := efd.ReportToJson(fetched.Result, fetched.Report)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+
+		jsonpath := jsonPath(cwd, fetched.Result)
+		os.MkdirAll(filepath.Dir(jsonpath), os.ModePerm)
+		ioutil.WriteFile(jsonpath, js, 0644)
 	}
 
 	return
 }
 
+func jsonPath(cwd string, result efd.SearchResult) string {
+	return filepath.Join(cwd, "data", fmt.Sprintf("%s.json", result.GenPTRSearchResultPath()))
+}
+
 func fileExists(path string) bool {
 	info, err := os.Stat(path)
 	if os.IsNotExist(err) {
@@ -0,0 +1,329 @@
+// Package compliance checks digital PTRs against the STOCK Act filing deadlines
+package compliance
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	efd "github.com/Individual-1/go-efd"
+)
+
+// Statutory limits on reporting a transaction, in days
+const (
+	DefaultTradeLimit        = 45
+	DefaultNotificationLimit = 30
+)
+
+// Rule identifies which deadline applied to a transaction
+type Rule string
+
+// Enumeration of deadlines a transaction can be held to
+const (
+	TradeRule        Rule = "trade"
+	NotificationRule Rule = "notification"
+)
+
+// Options configures a compliance check
+// Notified returns the date the filer was notified of a transaction, if known, as efdsearch does not publish it
+// Without it only the trade deadline can be checked
+// TradeLimit and NotificationLimit default to the statutory 45 and 30 days
+type Options struct {
+	Notified          func(result efd.SearchResult, t efd.Transaction) (time.Time, bool)
+	TradeLimit        int
+	NotificationLimit int
+}
+
+// Finding is the outcome of checking one transaction
+// The transaction is taken from the latest version of its report, while ReportID, ReportName, Submitted, and
+// Amendment describe the version in which it was first disclosed
+// Deadline is the earlier of the trade and notification deadlines, and Rule names the one which applied
+// DaysLate is the number of days Submitted falls after Deadline, and is 0 for transactions reported on time
+type Finding struct {
+	FirstName      string          `json:"firstname"`
+	LastName       string          `json:"lastname"`
+	ReportID       string          `json:"reportid"`
+	ReportName     string          `json:"reportname"`
+	Submitted      time.Time       `json:"submitted"`
+	Amendment      int             `json:"amendment"`
+	Transaction    efd.Transaction `json:"transaction"`
+	Notified       time.Time       `json:"notified"`
+	NotifiedKnown  bool            `json:"notifiedknown"`
+	Deadline       time.Time       `json:"deadline"`
+	Rule           Rule            `json:"rule"`
+	DaysAfterTrade int             `json:"daysaftertrade"`
+	DaysLate       int             `json:"dayslate"`
+	Late           bool            `json:"late"`
+}
+
+// FilerSummary totals the findings for one filer
+type FilerSummary struct {
+	FirstName    string `json:"firstname"`
+	LastName     string `json:"lastname"`
+	Reports      int    `json:"reports"`
+	Transactions int    `json:"transactions"`
+	Late         int    `json:"late"`
+	DaysLate     int    `json:"dayslate"`
+	MaxDaysLate  int    `json:"maxdayslate"`
+}
+
+// Failure is a digital PTR which could not be checked as it failed to fetch or parse
+type Failure struct {
+	FirstName  string `json:"firstname"`
+	LastName   string `json:"lastname"`
+	ReportID   string `json:"reportid"`
+	ReportName string `json:"reportname"`
+	Error      string `json:"error"`
+}
+
+// Report is the outcome of a compliance check
+// Findings are grouped by report in order of first submission, and Filers are ordered with the most late
+// transactions first
+// Failed lists the digital PTRs which could not be checked, so that a failed fetch is not mistaken for a timely filing
+// Undated counts transactions which could not be checked as their date was not parsed
+type Report struct {
+	Findings []Finding      `json:"findings"`
+	Filers   []FilerSummary `json:"filers"`
+	Failed   []Failure      `json:"failed,omitempty"`
+	Undated  int            `json:"undated"`
+}
+
+// version is a fetched digital PTR within an amendment chain
+type version struct {
+	result efd.SearchResult
+	report efd.ParsedReport
+}
+
+// Check compares the transactions of each fetched digital PTR against its submission date
+// Reports are grouped with their amendments using efd.ChainAmendments, and only the transactions of the latest version
+// of each report are checked, so corrected or withdrawn transactions are not counted twice
+// A transaction carried over from an earlier version is held to the date that version was submitted, while one first
+// disclosed in an amendment is held to the amendment's submission date
+// Digital PTRs with an error are listed in Failed, and results which are not digital PTRs are ignored
+func Check(fetched []efd.FetchResult, opts Options) Report {
+	var report Report
+
+	if opts.TradeLimit == 0 {
+		opts.TradeLimit = DefaultTradeLimit
+	}
+
+	if opts.NotificationLimit == 0 {
+		opts.NotificationLimit = DefaultNotificationLimit
+	}
+
+	var results []efd.SearchResult
+	reports := make(map[string]efd.ParsedReport)
+	for _, f := range fetched {
+		if f.Result.ReportFormat != efd.PTRFormat {
+			continue
+		}
+
+		if f.Err != nil {
+			report.Failed = append(report.Failed, Failure{FirstName: f.Result.FirstName, LastName: f.Result.LastName,
+				ReportID: f.Result.ReportID, ReportName: f.Result.ReportName, Error: f.Err.Error()})
+			continue
+		}
+
+		results = append(results, f.Result)
+		reports[f.Result.ReportID] = f.Report
+	}
+
+	filers := make(map[string]*FilerSummary)
+	var order []string
+
+	for _, chain := range efd.ChainAmendments(results) {
+		var versions []version
+		for _, result := range chain.Versions() {
+			versions = append(versions, version{result: result, report: reports[result.ReportID]})
+		}
+
+		key := strings.ToLower(chain.FirstName) + "\x00" + strings.ToLower(chain.LastName)
+		summary, ok := filers[key]
+		if !ok {
+			summary = &FilerSummary{FirstName: chain.FirstName, LastName: chain.LastName}
+			filers[key] = summary
+			order = append(order, key)
+		}
+		summary.Reports++
+
+		for _, finding := range checkChain(versions, opts, &report.Undated) {
+			summary.Transactions++
+			if finding.Late {
+				summary.Late++
+				summary.DaysLate += finding.DaysLate
+				if finding.DaysLate > summary.MaxDaysLate {
+					summary.MaxDaysLate = finding.DaysLate
+				}
+			}
+
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+
+	for _, key := range order {
+		report.Filers = append(report.Filers, *filers[key])
+	}
+
+	sort.SliceStable(report.Filers, func(a, b int) bool {
+		x, y := report.Filers[a], report.Filers[b]
+		if x.Late != y.Late {
+			return x.Late > y.Late
+		}
+
+		return x.DaysLate > y.DaysLate
+	})
+
+	return report
+}
+
+// checkChain checks the transactions of the latest version of a report, crediting each to the earliest version
+// in which it appeared
+// Repeated identical transactions are matched in order, so a second copy added by an amendment is credited to the
+// amendment
+func checkChain(versions []version, opts Options, undated *int) []Finding {
+	var findings []Finding
+
+	// firstSeen[key][n] is the index of the first version with at least n+1 copies of the transaction
+	firstSeen := make(map[string][]int)
+	for i, v := range versions {
+		counts := make(map[string]int)
+		for _, t := range v.report.Transactions {
+			key := t.Key()
+			counts[key]++
+			if len(firstSeen[key]) < counts[key] {
+				firstSeen[key] = append(firstSeen[key], i)
+			}
+		}
+	}
+
+	latest := versions[len(versions)-1]
+	seen := make(map[string]int)
+	for _, t := range latest.report.Transactions {
+		key := t.Key()
+		disclosed := versions[firstSeen[key][seen[key]]].result
+		seen[key]++
+
+		if t.Date.IsZero() {
+			*undated++
+			continue
+		}
+
+		findings = append(findings, checkTransaction(disclosed, t, opts))
+	}
+
+	return findings
+}
+
+// checkTransaction checks a single transaction against the submission date of the report which disclosed it
+func checkTransaction(result efd.SearchResult, t efd.Transaction, opts Options) Finding {
+	finding := Finding{
+		FirstName:   result.FirstName,
+		LastName:    result.LastName,
+		ReportID:    result.ReportID,
+		ReportName:  result.ReportName,
+		Submitted:   result.DateSubmitted,
+		Amendment:   result.Title.Amendment,
+		Transaction: t,
+		Rule:        TradeRule,
+	}
+
+	trade := civilDate(t.Date)
+	submitted := civilDate(result.DateSubmitted)
+
+	finding.Deadline = trade.AddDate(0, 0, opts.TradeLimit)
+	finding.DaysAfterTrade = daysBetween(trade, submitted)
+
+	if opts.Notified != nil {
+		if notified, ok := opts.Notified(result, t); ok {
+			finding.Notified = civilDate(notified)
+			finding.NotifiedKnown = true
+
+			if deadline := finding.Notified.AddDate(0, 0, opts.NotificationLimit); deadline.Before(finding.Deadline) {
+				finding.Deadline = deadline
+				finding.Rule = NotificationRule
+			}
+		}
+	}
+
+	if days := daysBetween(finding.Deadline, submitted); days > 0 {
+		finding.DaysLate = days
+		finding.Late = true
+	}
+
+	return finding
+}
+
+// Late returns the findings for transactions which were reported late
+func (r Report) Late() []Finding {
+	var late []Finding
+
+	for _, finding := range r.Findings {
+		if finding.Late {
+			late = append(late, finding)
+		}
+	}
+
+	return late
+}
+
+// String renders the report as text, with a line per filer followed by each late transaction and any reports which
+// could not be checked
+func (r Report) String() string {
+	var b strings.Builder
+
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Filer\tReports\tTransactions\tLate\tDays late\tMax days late")
+	for _, filer := range r.Filers {
+		fmt.Fprintf(w, "%s %s\t%d\t%d\t%d\t%d\t%d\n", filer.FirstName, filer.LastName, filer.Reports,
+			filer.Transactions, filer.Late, filer.DaysLate, filer.MaxDaysLate)
+	}
+	w.Flush()
+
+	late := r.Late()
+	if len(late) > 0 {
+		b.WriteString("\n")
+
+		w = tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "Filer\tTrade date\tSubmitted\tDeadline\tDays late\tTransaction\tReport")
+		for _, finding := range late {
+			t := finding.Transaction
+			fmt.Fprintf(w, "%s %s\t%s\t%s\t%s (%s)\t%d\t%s %s %s %s\t%s\n", finding.FirstName, finding.LastName,
+				t.Date.Format("2006-01-02"), finding.Submitted.Format("2006-01-02"),
+				finding.Deadline.Format("2006-01-02"), finding.Rule, finding.DaysLate, t.Type, t.Ticker, t.AssetName,
+				t.Amount, finding.ReportName)
+		}
+		w.Flush()
+	}
+
+	if len(r.Failed) > 0 {
+		fmt.Fprintf(&b, "\nReports which could not be checked: %d\n", len(r.Failed))
+		for _, failure := range r.Failed {
+			fmt.Fprintf(&b, "  %s %s, %s (%s): %s\n", failure.FirstName, failure.LastName, failure.ReportName,
+				failure.ReportID, failure.Error)
+		}
+	}
+
+	if r.Undated > 0 {
+		fmt.Fprintf(&b, "\n%d transactions without a date were not checked\n", r.Undated)
+	}
+
+	return b.String()
+}
+
+// ToJson marshals a Report into a JSON byte array
+func ToJson(report Report) ([]byte, error) {
+	return json.Marshal(report)
+}
+
+// civilDate drops the time of day from t, so that days are counted by calendar date
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween returns the number of calendar days from a to b, which must both be civil dates
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
@@ -0,0 +1,145 @@
+package compliance_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	efd "github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/compliance"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// fetchAll searches for and fetches every report served by a server with the provided reports
+func fetchAll(t *testing.T, reports []efdtest.Report, setup func(*efdtest.Server)) []efd.FetchResult {
+	t.Helper()
+
+	server := efdtest.NewServer(reports...)
+	defer server.Close()
+
+	if setup != nil {
+		setup(server)
+	}
+
+	ctx := context.Background()
+	client := server.EFDClient()
+
+	results, err := client.Search(ctx, efd.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	var fetched []efd.FetchResult
+	for f := range client.FetchAll(ctx, results, efd.FetchOptions{Workers: 2}) {
+		fetched = append(fetched, f)
+	}
+
+	return fetched
+}
+
+// ptrFixture returns the original PTR fixture
+func ptrFixture(t *testing.T) efdtest.Report {
+	t.Helper()
+
+	for _, report := range efdtest.Fixtures() {
+		if report.ID == efdtest.PTRFixtureID {
+			return report
+		}
+	}
+
+	t.Fatal("no PTR fixture")
+	return efdtest.Report{}
+}
+
+func TestCheckCreditsAmendments(t *testing.T) {
+	report := compliance.Check(fetchAll(t, efdtest.Fixtures(), nil), compliance.Options{})
+
+	if len(report.Findings) != 4 {
+		t.Fatalf("got %d findings, want the 4 transactions of the amended PTR", len(report.Findings))
+	}
+
+	late := report.Late()
+	if len(late) != 1 {
+		t.Fatalf("got %d late transactions, want 1", len(late))
+	}
+
+	nvda := late[0]
+	if nvda.Transaction.Ticker != "NVDA" || nvda.ReportID != efdtest.PTRAmendmentFixtureID || nvda.Amendment != 1 ||
+		nvda.DaysAfterTrade != 46 || nvda.DaysLate != 1 || nvda.Rule != compliance.TradeRule {
+		t.Errorf("unexpected late finding %+v", nvda)
+	}
+
+	for _, finding := range report.Findings {
+		if finding.Transaction.Ticker != "NVDA" && finding.ReportID != efdtest.PTRFixtureID {
+			t.Errorf("%s was credited to %s, want the original PTR", finding.Transaction.AssetName, finding.ReportID)
+		}
+	}
+
+	if len(report.Filers) != 1 || report.Filers[0].Late != 1 || report.Filers[0].MaxDaysLate != 1 {
+		t.Errorf("unexpected filers %+v", report.Filers)
+	}
+
+	if !strings.Contains(report.String(), "NVDA") {
+		t.Errorf("late transaction missing from report:\n%s", report)
+	}
+}
+
+func TestCheckDeadlineBoundary(t *testing.T) {
+	report := compliance.Check(fetchAll(t, efdtest.Fixtures(), nil), compliance.Options{TradeLimit: 46})
+
+	if late := report.Late(); len(late) != 0 {
+		t.Errorf("a transaction submitted on its deadline was flagged late: %+v", late)
+	}
+}
+
+func TestCheckNotificationDeadline(t *testing.T) {
+	notified := func(result efd.SearchResult, transaction efd.Transaction) (time.Time, bool) {
+		return transaction.Date.AddDate(0, 0, 5), true
+	}
+
+	report := compliance.Check(fetchAll(t, efdtest.Fixtures(), nil), compliance.Options{Notified: notified})
+
+	late := report.Late()
+	if len(late) != 1 || late[0].Rule != compliance.NotificationRule || late[0].DaysLate != 11 {
+		t.Errorf("got late findings %+v, want the NVDA purchase 11 days past its notification deadline", late)
+	}
+}
+
+func TestCheckSameDayPTRs(t *testing.T) {
+	second := ptrFixture(t)
+	second.ID = "0d6f3c2e-8a1b-4c7d-9e2f-3a4b5c6d7e8f"
+
+	report := compliance.Check(fetchAll(t, append(efdtest.Fixtures(), second), nil), compliance.Options{})
+	if len(report.Findings) != 8 {
+		t.Fatalf("got %d findings, want 8 across both PTRs", len(report.Findings))
+	}
+
+	apple := 0
+	for _, finding := range report.Findings {
+		if finding.Transaction.Ticker == "AAPL" {
+			apple++
+		}
+	}
+
+	if apple != 2 {
+		t.Errorf("AAPL was checked %d times, want 2", apple)
+	}
+}
+
+func TestCheckListsFailedReports(t *testing.T) {
+	ptr := ptrFixture(t)
+	fetched := fetchAll(t, []efdtest.Report{ptr}, func(server *efdtest.Server) {
+		server.InjectFailures(ptr.Path(), http.StatusNotFound)
+	})
+
+	report := compliance.Check(fetched, compliance.Options{})
+	if len(report.Failed) != 1 || report.Failed[0].ReportID != efdtest.PTRFixtureID {
+		t.Fatalf("got failures %+v, want the PTR", report.Failed)
+	}
+
+	if !strings.Contains(report.String(), "Reports which could not be checked: 1") {
+		t.Errorf("failure missing from report:\n%s", report)
+	}
+}
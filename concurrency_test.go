@@ -0,0 +1,124 @@
+package efd_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// blockingTransport holds the first disclaimer submission until its request context is done
+type blockingTransport struct {
+	once    sync.Once
+	entered chan struct{}
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPost && req.URL.Path == homePath {
+		first := false
+		b.once.Do(func() { first = true })
+
+		if first {
+			close(b.entered)
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+	}
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestReauthIsSingleFlight(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.EFDClient()
+	result := authedPTR(t, client)
+
+	server.ExpireSessions()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			_, errs[i] = client.HandleResultContext(ctx, result)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("worker %d: %v", i, err)
+		}
+	}
+
+	if got := server.Acceptances(); got != 2 {
+		t.Errorf("disclaimer was accepted %d times, want 2 as concurrent callers share one re-authentication", got)
+	}
+}
+
+func TestReauthSurvivesCancelledLeader(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	transport := &blockingTransport{entered: make(chan struct{})}
+	client := server.EFDClient(efd.WithTransport(transport))
+
+	var result efd.SearchResult
+	results, err := client.Search(context.Background(), efd.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	for _, r := range results {
+		if r.ReportID == efdtest.PTRFixtureID {
+			result = r
+		}
+	}
+
+	// The first caller accepts the disclaimer, and is cancelled while the second waits on it
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := client.HandleResultContext(leaderCtx, result)
+		leaderErr <- err
+	}()
+
+	<-transport.entered
+
+	waiterErr := make(chan error, 1)
+	var parsed efd.ParsedReport
+	go func() {
+		var err error
+		parsed, err = client.HandleResultContext(context.Background(), result)
+		waiterErr <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller got %v, want context.Canceled", err)
+	}
+
+	if err := <-waiterErr; err != nil {
+		t.Fatalf("waiting caller failed with the cancelled caller's error: %v", err)
+	}
+
+	if len(parsed.Transactions) != 4 {
+		t.Errorf("got %d transactions, want 4", len(parsed.Transactions))
+	}
+
+	if got := server.Acceptances(); got != 1 {
+		t.Errorf("disclaimer was accepted %d times, want 1", got)
+	}
+}
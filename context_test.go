@@ -0,0 +1,82 @@
+package efd_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// cancellingTransport calls cancel once the given number of search pages have been returned
+type cancellingTransport struct {
+	mu     sync.Mutex
+	pages  int
+	cancel context.CancelFunc
+}
+
+func (c *cancellingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || req.URL.Path != searchDataPath {
+		return resp, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pages--
+	if c.pages == 0 {
+		c.cancel()
+	}
+
+	return resp, err
+}
+
+func TestSearchStopsWhenCancelled(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := server.EFDClient(efd.WithTransport(&cancellingTransport{pages: 2, cancel: cancel}))
+
+	results, err := client.Search(ctx, efd.SearchQuery{PageSize: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %d results and error %v, want context.Canceled", len(results), err)
+	}
+
+	if got := server.Requests(searchDataPath); got != 2 {
+		t.Errorf("got %d search pages, want 2 of %d", got, len(efdtest.Fixtures()))
+	}
+}
+
+func TestIterateSearchStopsWhenCancelled(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	it, err := server.EFDClient().IterateSearch(ctx, efd.SearchQuery{PageSize: 1})
+	if err != nil {
+		t.Fatalf("IterateSearch: %v", err)
+	}
+
+	if !it.Next() {
+		t.Fatalf("Next: %v", it.Err())
+	}
+
+	cancel()
+
+	if it.Next() {
+		t.Error("Next advanced after the context was cancelled")
+	}
+
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", it.Err())
+	}
+}
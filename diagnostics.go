@@ -0,0 +1,66 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"fmt"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// SkippedRow describes a row which was dropped while parsing
+// Index is the zero-based row index within Section, and Raw is the HTML of the row, or its json for search results
+type SkippedRow struct {
+	Section string `json:"section"`
+	Index   int    `json:"index"`
+	Raw     string `json:"raw"`
+	Reason  string `json:"reason"`
+}
+
+// ParseReport lists everything that was skipped while parsing a page
+// UnknownColumns holds table headers which were not recognised and whose columns were ignored,
+// prefixed with the section name
+type ParseReport struct {
+	SkippedRows    []SkippedRow `json:"skippedrows,omitempty"`
+	UnknownColumns []string     `json:"unknowncolumns,omitempty"`
+}
+
+// Empty reports whether every row was parsed and every column recognised
+func (r ParseReport) Empty() bool {
+	return len(r.SkippedRows) == 0 && len(r.UnknownColumns) == 0
+}
+
+// WithStrictParsing makes any row skipped while parsing a search page or report fail with a ParseError
+// wrapping ErrRowSkipped, rather than being listed in the ParseReport
+// Unknown columns are still only reported, as they do not lose any rows
+func WithStrictParsing() ClientOption {
+	return func(o *clientOptions) {
+		o.strict = true
+	}
+}
+
+// skip records a row which was dropped, along with the reason why
+func (r *ParseReport) skip(section string, index int, raw string, reason string) {
+	r.SkippedRows = append(r.SkippedRows, SkippedRow{Section: section, Index: index, Raw: raw, Reason: reason})
+}
+
+// skipSelection records a dropped table row using its outer HTML as the raw value
+func (r *ParseReport) skipSelection(section string, index int, s *goquery.Selection, reason string) {
+	raw, _ := goquery.OuterHtml(s)
+	r.skip(section, index, raw, reason)
+}
+
+// checkStrict returns a ParseError for the first skipped row if strict parsing is enabled
+func (c *EFDClient) checkStrict(diag *ParseReport) error {
+	return checkStrict(c.opts.strict, diag)
+}
+
+// checkStrict returns a ParseError for the first skipped row in diag if strict is set
+func checkStrict(strict bool, diag *ParseReport) error {
+	if !strict || len(diag.SkippedRows) == 0 {
+		return nil
+	}
+
+	row := diag.SkippedRows[0]
+
+	return &ParseError{Section: row.Section, Row: row.Index, Err: fmt.Errorf("%w: %s", ErrRowSkipped, row.Reason)}
+}
@@ -0,0 +1,98 @@
+package efd_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// fixture returns the fixture report with the provided ID
+func fixture(t *testing.T, id string) efdtest.Report {
+	t.Helper()
+
+	for _, report := range efdtest.Fixtures() {
+		if report.ID == id {
+			return report
+		}
+	}
+
+	t.Fatalf("no fixture with ID %s", id)
+	return efdtest.Report{}
+}
+
+// fetchOnly serves a single report and fetches it with a client built with opts
+func fetchOnly(t *testing.T, report efdtest.Report, opts ...efd.ClientOption) (efd.ParsedReport, error) {
+	t.Helper()
+
+	server := efdtest.NewServer(report)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.EFDClient(opts...)
+
+	results, err := client.Search(ctx, efd.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+
+	return client.HandleResultContext(ctx, results[0])
+}
+
+// brokenPTR is the PTR fixture with an unparseable date in its first row
+func brokenPTR(t *testing.T) efdtest.Report {
+	report := fixture(t, efdtest.PTRFixtureID)
+	report.Body = strings.Replace(report.Body, "<td>03/10/2020</td>", "<td>soon</td>", 1)
+
+	return report
+}
+
+func TestDiagnosticsRecordSkippedRows(t *testing.T) {
+	parsed, err := fetchOnly(t, brokenPTR(t))
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	if len(parsed.Transactions) != 3 {
+		t.Errorf("got %d transactions, want 3", len(parsed.Transactions))
+	}
+
+	skipped := parsed.Diagnostics.SkippedRows
+	if len(skipped) != 1 {
+		t.Fatalf("got %d skipped rows, want 1", len(skipped))
+	}
+
+	if skipped[0].Index != 0 || !strings.Contains(skipped[0].Raw, "soon") || skipped[0].Reason == "" {
+		t.Errorf("unexpected skipped row %+v", skipped[0])
+	}
+}
+
+func TestStrictParsingFailsOnSkippedRows(t *testing.T) {
+	_, err := fetchOnly(t, brokenPTR(t), efd.WithStrictParsing())
+	if !errors.Is(err, efd.ErrRowSkipped) {
+		t.Fatalf("got error %v, want ErrRowSkipped", err)
+	}
+
+	var parseErr *efd.ParseError
+	if !errors.As(err, &parseErr) || parseErr.ReportID != efdtest.PTRFixtureID || parseErr.Row != 0 {
+		t.Errorf("got %#v, want a ParseError for row 0 of the PTR", parseErr)
+	}
+}
+
+func TestStrictParsingAcceptsCleanReports(t *testing.T) {
+	parsed, err := fetchOnly(t, fixture(t, efdtest.PTRFixtureID), efd.WithStrictParsing())
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	if len(parsed.Transactions) != 4 || !parsed.Diagnostics.Empty() {
+		t.Errorf("got %d transactions and diagnostics %+v", len(parsed.Transactions), parsed.Diagnostics)
+	}
+}
@@ -0,0 +1,246 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ReportDiff describes what changed between two parsed versions of a report
+// Transactions are matched by date, ticker, asset name, owner, and type, so a change to any of these shows as a
+// removed and an added transaction, while changes to other fields show as modified
+// Pages is only set when either version is a paper report
+type ReportDiff struct {
+	Added    []Transaction       `json:"added,omitempty"`
+	Removed  []Transaction       `json:"removed,omitempty"`
+	Modified []TransactionChange `json:"modified,omitempty"`
+	Pages    *PageDiff           `json:"pages,omitempty"`
+}
+
+// TransactionChange is a transaction present in both versions of a report with different field values
+type TransactionChange struct {
+	Old    Transaction   `json:"old"`
+	New    Transaction   `json:"new"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// FieldChange is the old and new value of one field of a transaction
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// PageDiff compares the pages of two versions of a paper report
+// Changed lists the page numbers present in both versions whose image differs
+// This needs both versions to have PageHashes, which Hashed indicates, otherwise only the page counts are compared
+type PageDiff struct {
+	OldCount int   `json:"oldcount"`
+	NewCount int   `json:"newcount"`
+	Hashed   bool  `json:"hashed"`
+	Changed  []int `json:"changed,omitempty"`
+}
+
+// transactionFields lists the transaction fields compared by DiffReports, along with how each is rendered
+var transactionFields = []struct {
+	name  string
+	value func(t Transaction) string
+}{
+	{"date", func(t Transaction) string { return t.Date.Format("2006-01-02") }},
+	{"owner", func(t Transaction) string { return t.Owner }},
+	{"ticker", func(t Transaction) string { return t.Ticker }},
+	{"assetname", func(t Transaction) string { return t.AssetName }},
+	{"assettype", func(t Transaction) string { return t.AssetType }},
+	{"type", func(t Transaction) string { return t.Type }},
+	{"amount", func(t Transaction) string { return t.Amount }},
+	{"comment", func(t Transaction) string { return t.Comment }},
+	{"option", func(t Transaction) string {
+		if t.Option == nil {
+			return ""
+		}
+
+		return fmt.Sprintf("%s %d %s %d %d %s", t.Option.Type, t.Option.Strike, t.Option.Expiration.Format("2006-01-02"),
+			t.Option.Contracts, t.Option.Shares, t.Option.Underlying)
+	}},
+}
+
+// DiffReports compares two parsed versions of a report, such as an original and its amendment or two fetches
+// of the same report
+// Transactions with the same key are matched in order, so repeated identical transactions are handled
+func DiffReports(old, new ParsedReport) ReportDiff {
+	var diff ReportDiff
+
+	pending := make(map[string][]int)
+	for i, t := range old.Transactions {
+		key := t.Key()
+		pending[key] = append(pending[key], i)
+	}
+
+	matched := make([]bool, len(old.Transactions))
+	for _, t := range new.Transactions {
+		key := t.Key()
+		if len(pending[key]) == 0 {
+			diff.Added = append(diff.Added, t)
+			continue
+		}
+
+		i := pending[key][0]
+		pending[key] = pending[key][1:]
+		matched[i] = true
+
+		if fields := diffTransaction(old.Transactions[i], t); len(fields) > 0 {
+			diff.Modified = append(diff.Modified, TransactionChange{Old: old.Transactions[i], New: t, Fields: fields})
+		}
+	}
+
+	for i, t := range old.Transactions {
+		if !matched[i] {
+			diff.Removed = append(diff.Removed, t)
+		}
+	}
+
+	if old.ReportFormat == PaperFormat || new.ReportFormat == PaperFormat {
+		diff.Pages = diffPages(old.Pages, new.Pages)
+	}
+
+	return diff
+}
+
+// Empty reports whether the two versions had no differences
+func (d ReportDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0 &&
+		(d.Pages == nil || (d.Pages.OldCount == d.Pages.NewCount && len(d.Pages.Changed) == 0))
+}
+
+// String renders the diff as text, with + for added, - for removed, and ~ for modified transactions
+func (d ReportDiff) String() string {
+	var b strings.Builder
+
+	if d.Empty() {
+		return "No changes\n"
+	}
+
+	if d.Pages != nil {
+		fmt.Fprintf(&b, "Pages: %d -> %d\n", d.Pages.OldCount, d.Pages.NewCount)
+		for _, page := range d.Pages.Changed {
+			fmt.Fprintf(&b, "  page %d changed\n", page)
+		}
+	}
+
+	for _, t := range d.Added {
+		fmt.Fprintf(&b, "+ %s\n", transactionSummary(t))
+	}
+
+	for _, t := range d.Removed {
+		fmt.Fprintf(&b, "- %s\n", transactionSummary(t))
+	}
+
+	for _, change := range d.Modified {
+		fmt.Fprintf(&b, "~ %s\n", transactionSummary(change.Old))
+		for _, field := range change.Fields {
+			fmt.Fprintf(&b, "    %s: %q -> %q\n", field.Field, field.Old, field.New)
+		}
+	}
+
+	return b.String()
+}
+
+// DiffToJson marshals a ReportDiff into a JSON byte array
+func DiffToJson(diff ReportDiff) ([]byte, error) {
+	return json.Marshal(diff)
+}
+
+// HashPaperPages sets the PageHashes of a paper report from its page images, so that DiffReports can detect pages
+// whose image changed
+// Every page is downloaded again and hashed as served, replacing any copy of the page already in sink, so a page
+// which changed since it was last saved is not hashed from the stale copy
+func (c *EFDClient) HashPaperPages(ctx context.Context, report *PaperReport, sink Sink) error {
+	if report.ReportID == "" {
+		return errors.New("efd paper report has no ReportID")
+	}
+
+	hashes := make([]string, len(report.PageURLs))
+	for i, pageURL := range report.PageURLs {
+		if pageURL == nil {
+			continue
+		}
+
+		u := c.resolvePageURL(pageURL)
+		body, err := c.fetchPaperPage(ctx, u)
+		if err != nil {
+			return fmt.Errorf("downloading page %d of %s: %w", i+1, report.ReportID, err)
+		}
+
+		err = sink.Put(ctx, paperPageName(report.ReportID, i+1, u), body)
+		if err != nil {
+			return fmt.Errorf("storing page %d of %s: %w", i+1, report.ReportID, err)
+		}
+
+		sum := sha256.Sum256(body)
+		hashes[i] = hex.EncodeToString(sum[:])
+	}
+
+	report.PageHashes = hashes
+
+	return nil
+}
+
+// Key identifies a transaction across versions of a report by its date, ticker, asset name, owner, and type
+func (t Transaction) Key() string {
+	parts := []string{t.Date.Format("2006-01-02"), t.Ticker, t.AssetName, t.Owner, t.Type}
+	for i, part := range parts {
+		parts[i] = normalizeKeyPart(part)
+	}
+
+	return strings.Join(parts, "\x00")
+}
+
+// normalizeKeyPart lowercases a field of a transaction key and collapses its whitespace, so that cosmetic
+// differences between versions of a report do not change the key
+func normalizeKeyPart(part string) string {
+	return strings.ToLower(strings.Join(strings.Fields(part), " "))
+}
+
+// diffTransaction lists the fields which differ between two versions of a transaction
+func diffTransaction(old, new Transaction) []FieldChange {
+	var fields []FieldChange
+
+	for _, field := range transactionFields {
+		o, n := field.value(old), field.value(new)
+		if o != n {
+			fields = append(fields, FieldChange{Field: field.name, Old: o, New: n})
+		}
+	}
+
+	return fields
+}
+
+// diffPages compares the pages of two versions of a paper report
+func diffPages(old, new PaperReport) *PageDiff {
+	diff := &PageDiff{OldCount: len(old.PageURLs), NewCount: len(new.PageURLs)}
+
+	diff.Hashed = len(old.PageHashes) == len(old.PageURLs) && len(new.PageHashes) == len(new.PageURLs) &&
+		len(old.PageHashes) > 0 && len(new.PageHashes) > 0
+
+	if !diff.Hashed {
+		return diff
+	}
+
+	for i := 0; i < diff.OldCount && i < diff.NewCount; i++ {
+		if old.PageHashes[i] != new.PageHashes[i] {
+			diff.Changed = append(diff.Changed, i+1)
+		}
+	}
+
+	return diff
+}
+
+// transactionSummary renders the identifying fields and amount of a transaction on one line
+func transactionSummary(t Transaction) string {
+	return fmt.Sprintf("%s %s %s %s %s %s", t.Date.Format("2006-01-02"), t.Owner, t.Ticker, t.AssetName, t.Type, t.Amount)
+}
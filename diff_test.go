@@ -0,0 +1,109 @@
+package efd_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// fetchFixtures searches for and fetches every report served to client, keyed by report ID
+func fetchFixtures(t *testing.T, client *efd.EFDClient) map[string]efd.FetchResult {
+	t.Helper()
+
+	ctx := context.Background()
+	results, err := client.Search(ctx, efd.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	fetched := make(map[string]efd.FetchResult)
+	for f := range client.FetchAll(ctx, results, efd.FetchOptions{Workers: 2}) {
+		if f.Err != nil {
+			t.Fatalf("fetching %s: %v", f.Result.ReportID, f.Err)
+		}
+
+		fetched[f.Result.ReportID] = f
+	}
+
+	return fetched
+}
+
+func TestDiffReportsAmendment(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	fetched := fetchFixtures(t, server.EFDClient())
+	diff := efd.DiffReports(fetched[efdtest.PTRFixtureID].Report, fetched[efdtest.PTRAmendmentFixtureID].Report)
+
+	if len(diff.Added) != 1 || diff.Added[0].Ticker != "NVDA" {
+		t.Errorf("got added %+v, want the NVDA purchase", diff.Added)
+	}
+
+	if len(diff.Removed) != 1 || diff.Removed[0].Ticker != "XOM" {
+		t.Errorf("got removed %+v, want the XOM exchange", diff.Removed)
+	}
+
+	if len(diff.Modified) != 1 || len(diff.Modified[0].Fields) != 1 || diff.Modified[0].Fields[0].Field != "amount" {
+		t.Errorf("got modified %+v, want one amount change", diff.Modified)
+	}
+
+	if !strings.Contains(diff.String(), "+ 2020-02-03 Self NVDA") {
+		t.Errorf("unexpected diff text:\n%s", diff)
+	}
+
+	if same := efd.DiffReports(fetched[efdtest.PTRFixtureID].Report, fetched[efdtest.PTRFixtureID].Report); !same.Empty() {
+		t.Errorf("a report differs from itself: %s", same)
+	}
+}
+
+func TestTransactionKeyIgnoresCosmeticChanges(t *testing.T) {
+	a := efd.Transaction{Ticker: "AAPL", AssetName: "Apple Inc.", Owner: "Spouse", Type: "Sale (Full)"}
+	b := efd.Transaction{Ticker: "aapl", AssetName: " Apple  Inc. ", Owner: "SPOUSE", Type: "Sale  (Full)"}
+
+	if a.Key() != b.Key() {
+		t.Errorf("keys differ: %q and %q", a.Key(), b.Key())
+	}
+}
+
+func TestHashPaperPagesDetectsChangedPage(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.EFDClient()
+	old := fetchFixtures(t, client)[efdtest.PaperFixtureID].Report
+
+	sink := newMemSink()
+	if err := client.HashPaperPages(ctx, &old.Pages, sink); err != nil {
+		t.Fatalf("HashPaperPages: %v", err)
+	}
+
+	changedPage := []byte("GIF89a rescanned page")
+	server.AddFile(paperPagePath(2), efdtest.File{ContentType: "image/gif", Body: changedPage})
+
+	new := old
+	new.Pages.PageHashes = nil
+	if err := client.HashPaperPages(ctx, &new.Pages, sink); err != nil {
+		t.Fatalf("HashPaperPages: %v", err)
+	}
+
+	for i := 1; i <= efdtest.PaperFixturePages; i++ {
+		if got := server.Requests(paperPagePath(i)); got != 2 {
+			t.Errorf("page %d was requested %d times, want 2", i, got)
+		}
+	}
+
+	diff := efd.DiffReports(old, new)
+	if diff.Pages == nil || !diff.Pages.Hashed || len(diff.Pages.Changed) != 1 || diff.Pages.Changed[0] != 2 {
+		t.Errorf("got page diff %+v, want page 2 changed", diff.Pages)
+	}
+
+	stored, err := sink.Get(ctx, efdtest.PaperFixtureID+"/page_002.gif")
+	if err != nil || !bytes.Equal(stored, changedPage) {
+		t.Errorf("sink holds %q (%v), want the changed page", stored, err)
+	}
+}
@@ -2,9 +2,13 @@
 package efd
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math/rand"
 	"net/http"
 	"net/http/cookiejar"
@@ -13,6 +17,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/net/html"
@@ -71,9 +76,19 @@ type anchorData struct {
 
 // EFDClient is a wrapper struct containing state and parameters
 // for interacting with the efdsearch system
+// An EFDClient is safe for concurrent use by multiple goroutines, which share a single session
 type EFDClient struct {
-	jar           http.CookieJar
-	client        *http.Client
+	// mu guards the session fields jar, client, authed, generation, and authCall
+	mu         sync.RWMutex
+	jar        http.CookieJar
+	client     *http.Client
+	generation uint64
+	authCall   *authCall
+
+	searchClient  *http.Client
+	transport     http.RoundTripper
+	limiter       *rateLimiter
+	opts          clientOptions
 	baseURL       *url.URL
 	homeURL       *url.URL
 	searchURL     *url.URL
@@ -85,14 +100,35 @@ type EFDClient struct {
 	authed bool
 }
 
+// Defaults used when creating a client, and by the offline parsers
+const (
+	defaultDateLayout string = "01/02/2006"
+	defaultBaseURL    string = "https://efdsearch.senate.gov"
+)
+
+// Section names used for errors and skipped rows in search results and paper reports
+const (
+	searchDataSection = "search data"
+	paperSection      = "Pages"
+)
+
+// authCall is an in-flight acceptance of the disclaimer which concurrent callers wait on
+// cancelled is set if it failed because the context of the caller making it was done
+type authCall struct {
+	done      chan struct{}
+	err       error
+	cancelled bool
+}
+
 // CreateEFDClient initializes and returns an EFDClient object
 // Empty inputs for useragent or datelayout will set default values
-func CreateEFDClient(userAgent string, dateLayout string) EFDClient {
-	var c EFDClient
+// Additional ClientOptions can be provided to change the target host or underlying HTTP client
+func CreateEFDClient(userAgent string, dateLayout string, opts ...ClientOption) *EFDClient {
+	c := &EFDClient{}
 
 	if dateLayout == "" {
 		// Default to 01/02/2006
-		dateLayout = "01/02/2006"
+		dateLayout = defaultDateLayout
 	}
 
 	if userAgent == "" {
@@ -100,22 +136,31 @@ func CreateEFDClient(userAgent string, dateLayout string) EFDClient {
 		userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:75.0) Gecko/20100101 Firefox/75.0"
 	}
 
-	c.initParam(userAgent, dateLayout)
+	c.initParam(userAgent, dateLayout, opts)
 
 	return c
 }
 
 // initParam is an initializer function for an EFDClient struct, it sets up some parameters of the client
 // and prepares the underlying HTTP client for use
-func (c *EFDClient) initParam(userAgent string, dateLayout string) {
+func (c *EFDClient) initParam(userAgent string, dateLayout string, opts []ClientOption) {
 	c.dateLayout = dateLayout
 	c.userAgent = userAgent
 
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+
+	c.transport = c.opts.resolveTransport()
+	c.limiter = newRateLimiter(c.opts.rateLimit)
+
 	c.jar, _ = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
 
-	c.client = &http.Client{Jar: c.jar}
+	c.client = c.opts.newHTTPClient(c.transport, c.jar)
+
+	// The search endpoint is called with a generated csrf token rather than the session cookies
+	c.searchClient = c.opts.newHTTPClient(c.transport, nil)
 
-	const baseURLString string = "https://efdsearch.senate.gov"
 	const homeURLString string = "/search/home/"
 	const searchURLString string = "/search/"
 	const searchDataURLString string = "/search/report/data/"
@@ -124,7 +169,12 @@ func (c *EFDClient) initParam(userAgent string, dateLayout string) {
 	var searchURLComponent, _ = url.Parse(searchURLString)
 	var searchDataURLComponent, _ = url.Parse(searchDataURLString)
 
-	c.baseURL, _ = url.Parse(baseURLString)
+	if c.opts.baseURL != nil {
+		c.baseURL = &url.URL{Scheme: c.opts.baseURL.Scheme, Host: c.opts.baseURL.Host}
+	} else {
+		c.baseURL, _ = url.Parse(defaultBaseURL)
+	}
+
 	c.homeURL = c.baseURL.ResolveReference(homeURLComponent)
 	c.searchURL = c.baseURL.ResolveReference(searchURLComponent)
 	c.searchDataURL = c.baseURL.ResolveReference(searchDataURLComponent)
@@ -136,7 +186,96 @@ func (c *EFDClient) initParam(userAgent string, dateLayout string) {
 // and setting up session data for report retrieval
 // This doesn't need to be called explicitly, but can be
 func (c *EFDClient) AcceptDisclaimer() error {
-	csrftoken, err := c.parseCSRFToken(c.homeURL)
+	return c.AcceptDisclaimerContext(context.Background())
+}
+
+// AcceptDisclaimerContext is AcceptDisclaimer with a context controlling cancellation of the underlying requests
+// If another goroutine is already accepting the disclaimer, this waits for and returns its result instead
+func (c *EFDClient) AcceptDisclaimerContext(ctx context.Context) error {
+	c.mu.RLock()
+	generation := c.generation
+	c.mu.RUnlock()
+
+	return c.refreshSession(ctx, generation)
+}
+
+// ensureSession accepts the disclaimer if the session has not yet done so
+// It returns the generation of the session, for passing to refreshSession if the session is later rejected
+func (c *EFDClient) ensureSession(ctx context.Context) (uint64, error) {
+	c.mu.RLock()
+	authed, generation := c.authed, c.generation
+	c.mu.RUnlock()
+
+	if authed {
+		return generation, nil
+	}
+
+	if err := c.refreshSession(ctx, generation); err != nil {
+		return 0, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.generation, nil
+}
+
+// refreshSession accepts the disclaimer unless the session has already been refreshed since the stale generation
+// Only one acceptance is in flight at a time, concurrent callers wait for its result
+// If the acceptance failed only because the context of the caller making it was done, a waiting caller whose own
+// context is still live makes the acceptance again
+func (c *EFDClient) refreshSession(ctx context.Context, stale uint64) error {
+	for {
+		c.mu.Lock()
+		if c.authed && c.generation != stale {
+			c.mu.Unlock()
+			return nil
+		}
+
+		if call := c.authCall; call != nil {
+			c.mu.Unlock()
+
+			select {
+			case <-call.done:
+				if call.cancelled && ctx.Err() == nil {
+					continue
+				}
+
+				return call.err
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		call := &authCall{done: make(chan struct{})}
+		c.authCall = call
+		client := c.client
+		c.mu.Unlock()
+
+		err := c.acceptDisclaimer(ctx, client)
+
+		c.mu.Lock()
+		// If the client was cleared while we were accepting, the new session still needs to accept the disclaimer
+		if c.client == client {
+			c.authed = err == nil
+			if err == nil {
+				c.generation++
+			}
+		}
+		c.authCall = nil
+		c.mu.Unlock()
+
+		call.err = err
+		call.cancelled = err != nil && ctx.Err() != nil
+		close(call.done)
+
+		return err
+	}
+}
+
+// acceptDisclaimer submits the disclaimer form using the provided session client
+func (c *EFDClient) acceptDisclaimer(ctx context.Context, client *http.Client) error {
+	csrftoken, err := c.parseCSRFToken(ctx, client, c.homeURL)
 	if err != nil {
 		return err
 	}
@@ -145,7 +284,7 @@ func (c *EFDClient) AcceptDisclaimer() error {
 	data.Set("prohibition_agreement", "1")
 	data.Set("csrfmiddlewaretoken", csrftoken)
 
-	req, err := http.NewRequest("POST", c.homeURL.String(), strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.homeURL.String(), strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
@@ -155,73 +294,113 @@ func (c *EFDClient) AcceptDisclaimer() error {
 	req.Header.Add("Content-Length", strconv.Itoa(len(data.Encode())))
 	req.Header.Add("User-Agent", c.userAgent)
 
-	_, err = c.client.Do(req)
+	resp, err := c.do(ctx, client, req, nil)
 	if err != nil {
 		return err
 	}
 
-	c.authed = true
+	if resp.StatusCode != http.StatusOK {
+		return newHTTPStatusError(resp)
+	}
+
+	// A rejected submission serves the disclaimer form again rather than an error status
+	if c.servesDisclaimer(resp) {
+		return ErrNotAuthenticated
+	}
 
 	return nil
 }
 
 // SearchSenatorPTR is a wrapper around searchReportData which retrieves a list of Senator's PTRs for a given timeframe
 func (c *EFDClient) SearchSenatorPTR(startTime time.Time, endTime time.Time) ([]SearchResult, error) {
-	return c.SearchReportData("", "", []FilerType{SenatorFiler}, "", []ReportType{PeriodicTransactionReport}, startTime, endTime)
+	return c.SearchSenatorPTRContext(context.Background(), startTime, endTime)
+}
+
+// SearchSenatorPTRContext is SearchSenatorPTR with a context controlling cancellation of the underlying requests
+func (c *EFDClient) SearchSenatorPTRContext(ctx context.Context, startTime time.Time, endTime time.Time) ([]SearchResult, error) {
+	return c.Search(ctx, SearchQuery{
+		FilerTypes:  []FilerType{SenatorFiler},
+		ReportTypes: []ReportType{PeriodicTransactionReport},
+		StartTime:   startTime,
+		EndTime:     endTime,
+	})
 }
 
-// SearchReportData is a wrapper around searchReportDataPaged which automatically iterates over the full number of results
+// SearchReportData is a wrapper around Search which automatically iterates over the full number of results
+// For large searches, IterateSearch avoids holding every result in memory
 func (c *EFDClient) SearchReportData(firstName string, lastName string, filerTypes []FilerType, state string, reportTypes []ReportType,
 	startTime time.Time, endTime time.Time) ([]SearchResult, error) {
-	var finalResults []SearchResult
-	var err error
-	const length int = 100
+	return c.SearchReportDataContext(context.Background(), firstName, lastName, filerTypes, state, reportTypes, startTime, endTime)
+}
 
-	start := 0
-	remainder := 1
+// SearchReportDataContext is SearchReportData with a context controlling cancellation of the underlying requests
+func (c *EFDClient) SearchReportDataContext(ctx context.Context, firstName string, lastName string, filerTypes []FilerType,
+	state string, reportTypes []ReportType, startTime time.Time, endTime time.Time) ([]SearchResult, error) {
+	return c.Search(ctx, SearchQuery{
+		FirstName:   firstName,
+		LastName:    lastName,
+		FilerTypes:  filerTypes,
+		State:       state,
+		ReportTypes: reportTypes,
+		StartTime:   startTime,
+		EndTime:     endTime,
+	})
+}
 
-	// As long as (Total records - start - length) > 0, we have more records to read
-	for remainder > 0 {
-		var results []SearchResult
-		results, remainder, err = c.searchReportDataPaged(firstName, lastName, filerTypes, state, reportTypes, startTime, endTime, start, length)
-		if err != nil {
-			return nil, err
-		}
+// Search validates the query and returns every matching search result
+// The context is checked between pages, so a cancelled search returns the context error rather than partial results
+// For large searches, IterateSearch avoids holding every result in memory
+func (c *EFDClient) Search(ctx context.Context, q SearchQuery) ([]SearchResult, error) {
+	var finalResults []SearchResult
 
-		finalResults = append(finalResults, results...)
+	it, err := c.IterateSearch(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	for it.Next() {
+		finalResults = append(finalResults, it.Result())
+	}
 
-		start = start + length
+	if err = it.Err(); err != nil {
+		return nil, err
 	}
 
 	return finalResults, nil
 }
 
-// searchReportDataPaged calls the /search/report/data/ endpoint to get a list of records for the provided inputs
-// For both Time inputs, only Day, Month, and Year will be used
+// searchReportDataPaged calls the /search/report/data/ endpoint to get a list of records for the provided query
+// The query is expected to be validated and have defaults applied
 // start and length indicate the result number to start from and length to go
-// Returns search results, number of records remaining, and error status
-func (c *EFDClient) searchReportDataPaged(firstName string, lastName string, filerTypes []FilerType, state string, reportTypes []ReportType,
-	startTime time.Time, endTime time.Time, start int, length int) ([]SearchResult, int, error) {
+// Returns search results, number of records remaining, and error status, with malformed records recorded in diag
+func (c *EFDClient) searchReportDataPaged(ctx context.Context, q SearchQuery, start int, length int,
+	diag *ParseReport) ([]SearchResult, int, error) {
 	csrfToken := c.genCSRFToken()
 
-	startTimeString := fmt.Sprintf("%02d/%02d/%04d 00:00:00",
-		startTime.Month(), startTime.Day(), startTime.Year())
+	// Only Day, Month, and Year are used, open ends of the range are left empty
+	var startTimeString, endTimeString string
+	if !q.StartTime.IsZero() {
+		startTimeString = fmt.Sprintf("%02d/%02d/%04d 00:00:00",
+			q.StartTime.Month(), q.StartTime.Day(), q.StartTime.Year())
+	}
 
-	endTimeString := fmt.Sprintf("%02d/%02d/%04d 23:59:59",
-		endTime.Month(), endTime.Day(), endTime.Year())
+	if !q.EndTime.IsZero() {
+		endTimeString = fmt.Sprintf("%02d/%02d/%04d 23:59:59",
+			q.EndTime.Month(), q.EndTime.Day(), q.EndTime.Year())
+	}
 
 	data := url.Values{}
 
 	// Target first name
-	data.Set("first_name", firstName)
+	data.Set("first_name", q.FirstName)
 	// Target last name
-	data.Set("last_name", lastName)
+	data.Set("last_name", q.LastName)
 	// Target filer type (Format is [1, 2])
-	data.Set("filer_types", intEnumArrayToString(filerTypes, ","))
+	data.Set("filer_types", intEnumArrayToString(q.FilerTypes, ","))
 	// Target state represented
-	data.Set("senator_state", state)
+	data.Set("senator_state", q.State)
 	// Report type (Format is [11, 12])
-	data.Set("report_types", intEnumArrayToString(reportTypes, ","))
+	data.Set("report_types", intEnumArrayToString(q.ReportTypes, ","))
 	// Beginning of date range to search (Format is MM/DD/YYYY HH:MM:SS)
 	data.Set("submitted_start_date", startTimeString)
 	// End of date range to search (Format is MM/DD/YYYY HH:MM:SS)
@@ -233,7 +412,7 @@ func (c *EFDClient) searchReportDataPaged(firstName string, lastName string, fil
 	// CSRF token
 	data.Set("csrftoken", csrfToken)
 
-	req, err := http.NewRequest("POST", c.searchDataURL.String(), strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.searchDataURL.String(), strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -245,24 +424,64 @@ func (c *EFDClient) searchReportDataPaged(firstName string, lastName string, fil
 	req.Header.Add("X-CSRFToken", csrfToken)
 	req.AddCookie(&http.Cookie{Name: "csrftoken", Value: csrfToken})
 
-	resp, err := http.DefaultClient.Do(req)
+	// efdsearch serves html error pages in place of json when it is struggling, so treat those as transient
+	resp, err := c.do(ctx, c.searchClient, req, func(resp *http.Response, body []byte) error {
+		if resp.Header.Get("content-type") != "application/json" {
+			return retryableError{fmt.Errorf("%w: expected json from %s, got %q",
+				ErrUnexpectedContentType, c.searchDataURL, resp.Header.Get("content-type"))}
+		}
+
+		return nil
+	})
 	if err != nil {
 		return nil, 0, err
 	}
 
-	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, newHTTPStatusError(resp)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = c.archiveResponse(ctx, "", resp, body)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	if resp.Header.Get("content-type") != "application/json" {
-		return nil, 0, errors.New("Response content type is not json")
+	results, searchResults, err := c.parseSearchData(bytes.NewReader(body), start, diag)
+	if err == nil {
+		err = c.checkStrict(diag)
 	}
 
+	if err != nil {
+		return nil, 0, err
+	}
+
+	remainder := results.RecordsTotal - start - length
+
+	return searchResults, remainder, nil
+}
+
+// parseSearchData decodes a page of search results, resolving report links against the client base URL
+// The raw SearchResults are also returned for the record counts
+// Malformed records are skipped and recorded in diag, indexed from start
+func (c *EFDClient) parseSearchData(r io.Reader, start int, diag *ParseReport) (SearchResults, []SearchResult, error) {
 	var results SearchResults
 	var searchResults []SearchResult
-	err = json.NewDecoder(resp.Body).Decode(&results)
+
+	err := json.NewDecoder(r).Decode(&results)
 	if err != nil {
-		return nil, 0, err
+		return results, nil, &ParseError{Section: searchDataSection, Row: -1, Err: err}
 	} else if results.Result != "ok" {
-		return nil, 0, errors.New("Search results endpoint returned error status")
+		return results, nil, fmt.Errorf("%w: %q", ErrSearchFailed, results.Result)
+	}
+
+	skip := func(i int, result []string, reason string) {
+		raw, _ := json.Marshal(result)
+		diag.skip(searchDataSection, start+i, string(raw), reason)
 	}
 
 	searchResults = make([]SearchResult, len(results.Data))
@@ -270,22 +489,26 @@ func (c *EFDClient) searchReportDataPaged(firstName string, lastName string, fil
 		var sResult *SearchResult = &searchResults[i]
 		// If this array is not 5 strings long, then it is malformed
 		if len(result) != 5 {
-			break
+			skip(i, result, fmt.Sprintf("record has %d fields, expected 5", len(result)))
+			continue
 		}
 
 		sResult.DateSubmitted, err = time.Parse(c.dateLayout, result[4])
 		if err != nil {
-			break
+			skip(i, result, "could not parse date submitted")
+			continue
 		}
 
 		anchor, err := c.parseAnchor(result[3])
 		if err != nil || anchor.HREF == "" {
-			break
+			skip(i, result, "could not parse report link")
+			continue
 		}
 
 		docURL, err := url.Parse(anchor.HREF)
 		if err != nil {
-			break
+			skip(i, result, "could not parse report URL")
+			continue
 		}
 
 		sResult.FileURL = c.baseURL.ResolveReference(docURL)
@@ -297,6 +520,7 @@ func (c *EFDClient) searchReportDataPaged(firstName string, lastName string, fil
 		}
 
 		sResult.ReportName = anchor.Text
+		sResult.Title = parseReportTitle(anchor.Text, c.dateLayout)
 		sResult.ReportID = path.Base(sResult.FileURL.Path)
 		sResult.FirstName = strings.ToLower(result[0])
 		sResult.LastName = strings.ToLower(result[1])
@@ -312,25 +536,31 @@ func (c *EFDClient) searchReportDataPaged(firstName string, lastName string, fil
 		}
 	}
 
-	remainder := results.RecordsTotal - start - length
-
-	return searchFiltered, remainder, nil
+	return results, searchFiltered, nil
 }
 
 // HandleResult is a wrapper around other handler types, selecting one based on the ReportType in the request
 // It returns a ParsedReport type
 func (c *EFDClient) HandleResult(result SearchResult) (ParsedReport, error) {
+	return c.HandleResultContext(context.Background(), result)
+}
+
+// HandleResultContext is HandleResult with a context controlling cancellation of the underlying requests
+func (c *EFDClient) HandleResultContext(ctx context.Context, result SearchResult) (ParsedReport, error) {
 	var parsedReport ParsedReport
 	var err error
 
 	parsedReport.ReportFormat = result.ReportFormat
 	switch result.ReportFormat {
 	case PTRFormat:
-		parsedReport.Transactions, err = c.HandlePTRSearchResult(result)
+		parsedReport.Transactions, err = c.handlePTRSearchResult(ctx, result, &parsedReport.Diagnostics)
 	case AnnualFormat:
-		parsedReport.Transactions, err = c.HandleAnnualSearchResult(result)
+		parsedReport.Annual, err = c.handleAnnualSearchResult(ctx, result, &parsedReport.Diagnostics)
+		parsedReport.Transactions = parsedReport.Annual.allTransactions()
 	case PaperFormat:
-		parsedReport.Pages, err = c.HandlePaperSearchResult(result)
+		parsedReport.Pages, err = c.handlePaperSearchResult(ctx, result, &parsedReport.Diagnostics)
+	case DueDateExtensionFormat:
+		parsedReport.Extension, err = c.handleExtensionSearchResult(ctx, result, &parsedReport.Diagnostics)
 	}
 
 	return parsedReport, err
@@ -338,288 +568,204 @@ func (c *EFDClient) HandleResult(result SearchResult) (ParsedReport, error) {
 
 // HandlePTRSearchResult takes a SearchResult struct and parses out transaction from the digital PTR
 func (c *EFDClient) HandlePTRSearchResult(result SearchResult) ([]Transaction, error) {
-	var ptrTransactions []Transaction
-	var err error
+	return c.HandlePTRSearchResultContext(context.Background(), result)
+}
 
-	if !c.authed {
-		err = c.AcceptDisclaimer()
-		if err != nil {
-			return nil, err
-		}
-	}
+// HandlePTRSearchResultContext is HandlePTRSearchResult with a context controlling cancellation of the request and parse
+func (c *EFDClient) HandlePTRSearchResultContext(ctx context.Context, result SearchResult) ([]Transaction, error) {
+	var diag ParseReport
+	return c.handlePTRSearchResult(ctx, result, &diag)
+}
 
-	resp, err := c.client.Get(result.FileURL.String())
-	if err != nil || resp.StatusCode == http.StatusForbidden {
-		c.authed = false
+// handlePTRSearchResult fetches and parses a digital PTR, recording skipped rows and unrecognised headers in diag
+func (c *EFDClient) handlePTRSearchResult(ctx context.Context, result SearchResult, diag *ParseReport) ([]Transaction, error) {
+	doc, err := c.fetchDocument(ctx, result, result.FileURL)
+	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
+	transactions, err := c.parsePTRDocument(ctx, doc, diag)
+	if err == nil {
+		err = c.checkStrict(diag)
+	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, withReportID(err, result.ReportID)
 	}
 
-	// PTR table rows have 9 elements each
+	return transactions, nil
+}
+
+// parsePTRDocument parses every transaction table of a digital PTR
+func (c *EFDClient) parsePTRDocument(ctx context.Context, doc *goquery.Document, diag *ParseReport) ([]Transaction, error) {
+	// PTR tables typically have 9 columns
 	// Transaction #, Transaction Date, Owner, Ticker, Asset Name, Asset Type, Transaction Type, Amount, and Comment
-	trs := doc.Find("div.table-responsive table.table tbody tr")
-	ptrTransactions = make([]Transaction, trs.Length())
-	trs.Each(func(i int, s *goquery.Selection) {
-		var ptrTransaction *Transaction = &ptrTransactions[i]
-		tds := s.ChildrenFiltered("td")
-		if tds.Length() != 9 {
-			return
-		}
+	var ptrTransactions []Transaction
+	var err error
 
-		tds.EachWithBreak(func(j int, t *goquery.Selection) bool {
-
-			switch j {
-			case 1:
-				// Transaction Date
-				return c.handleTransactionCell(ptrTransaction, t, trDateCell)
-			case 2:
-				// Owner
-				return c.handleTransactionCell(ptrTransaction, t, ownerCell)
-			case 3:
-				// Ticker
-				return c.handleTransactionCell(ptrTransaction, t, tickerCell)
-			case 4:
-				// Asset Name
-				return c.handleTransactionCell(ptrTransaction, t, assetNameCell)
-			case 5:
-				// Asset Type
-				return c.handleTransactionCell(ptrTransaction, t, assetTypeCell)
-			case 6:
-				// Transaction Type
-				return c.handleTransactionCell(ptrTransaction, t, trTypeCell)
-			case 7:
-				// Amount
-				return c.handleTransactionCell(ptrTransaction, t, amountCell)
-			case 8:
-				// Comment
-				if c.handleTransactionCell(ptrTransaction, t, commentCell) {
-					c.handleTransactionCell(ptrTransaction, t, validCell)
-				} else {
-					return false
-				}
-			}
+	tables := doc.Find("div.table-responsive table.table")
+	tables.EachWithBreak(func(i int, table *goquery.Selection) bool {
+		var transactions []Transaction
+
+		transactions, err = c.parseTransactionTable(ctx, "Transactions", table, diag)
+		ptrTransactions = append(ptrTransactions, transactions...)
 
-			return true
-		})
+		return err == nil
 	})
 
-	ptrFiltered := ptrTransactions[:0]
-	for _, record := range ptrTransactions {
-		if record.Valid {
-			ptrFiltered = append(ptrFiltered, record)
-		}
+	if err != nil {
+		return nil, err
 	}
 
-	return ptrFiltered, nil
+	return ptrTransactions, nil
 }
 
 // HandleAnnualSearchResult takes a SearchResult struct and parses out transaction from the digital Annual report
-// Structured very similarly to HandlePTRSearchResult with some minor column ordering differences
-// TODO: Can we consolidate this and PTRSearchResult handler?
+// Parts 4a and 4b contain transactions, with some minor column ordering differences to a PTR
+// HandleAnnualDisclosure can be used to parse the other parts of the report
 func (c *EFDClient) HandleAnnualSearchResult(result SearchResult) ([]Transaction, error) {
-	var ptrTransactions []Transaction
-	var regTransactions []Transaction
-	var totalTransactions []Transaction
-	var err error
+	return c.HandleAnnualSearchResultContext(context.Background(), result)
+}
 
-	if !c.authed {
-		err = c.AcceptDisclaimer()
-		if err != nil {
-			return nil, err
-		}
-	}
+// HandleAnnualSearchResultContext is HandleAnnualSearchResult with a context controlling cancellation of the request and parse
+func (c *EFDClient) HandleAnnualSearchResultContext(ctx context.Context, result SearchResult) ([]Transaction, error) {
+	var diag ParseReport
 
-	resp, err := c.client.Get(result.FileURL.String())
-	if err != nil || resp.StatusCode == http.StatusForbidden {
-		c.authed = false
+	report, err := c.handleAnnualSearchResult(ctx, result, &diag)
+	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
+	return report.allTransactions(), nil
+}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+// HandleAnnualDisclosure takes a SearchResult struct and parses every part of the digital Annual report
+func (c *EFDClient) HandleAnnualDisclosure(result SearchResult) (AnnualDisclosure, error) {
+	return c.HandleAnnualDisclosureContext(context.Background(), result)
+}
+
+// HandleAnnualDisclosureContext is HandleAnnualDisclosure with a context controlling cancellation of the request and parse
+func (c *EFDClient) HandleAnnualDisclosureContext(ctx context.Context, result SearchResult) (AnnualDisclosure, error) {
+	var diag ParseReport
+	return c.handleAnnualSearchResult(ctx, result, &diag)
+}
+
+// handleAnnualSearchResult fetches and parses a digital Annual report, recording skipped rows and unrecognised
+// headers in diag
+func (c *EFDClient) handleAnnualSearchResult(ctx context.Context, result SearchResult, diag *ParseReport) (AnnualDisclosure, error) {
+	doc, err := c.fetchDocument(ctx, result, result.FileURL)
 	if err != nil {
-		return nil, err
+		return AnnualDisclosure{}, err
 	}
 
-	// Section 4a PTR table rows have 8 elements each
-	// Transaction #, Transaction Date, Owner, Ticker, Asset Name, Transaction Type, Amount, and Comment
-	// Section 4b Transactions have 8 elements each
-	// Transaction #, Owner, Ticker, Asset Name, Transaction Type, Transaction Date, Amount, and Comment
-	hdrs := doc.Find("section.card div.card-body h3.h4")
-	hdrs.Each(func(i int, s *goquery.Selection) {
-		title, _ := c.removeHTMLSelection(s)
-
-		// Handle modified PTR format in Annual Reports
-		if title == "Part 4a. Periodic Transaction Report Summary" {
-			section := s.Parent().Parent()
-			trs := section.Find("div.table-responsive table.table tbody tr")
-			ptrTransactions = make([]Transaction, trs.Length())
-			trs.Each(func(u int, r *goquery.Selection) {
-				var ptrTransaction *Transaction = &ptrTransactions[u]
-				tds := r.ChildrenFiltered("td")
-				if tds.Length() != 9 {
-					return
-				}
+	report, err := c.parseAnnualDocument(ctx, doc, diag)
+	if err == nil {
+		err = c.checkStrict(diag)
+	}
 
-				tds.EachWithBreak(func(j int, t *goquery.Selection) bool {
-
-					switch j {
-					case 0:
-						// whitespace
-					case 1:
-						// Transaction Number
-					case 2:
-						// Transaction Date
-						return c.handleTransactionCell(ptrTransaction, t, trDateCell)
-					case 3:
-						// Owner
-						return c.handleTransactionCell(ptrTransaction, t, ownerCell)
-					case 4:
-						// Ticker
-						return c.handleTransactionCell(ptrTransaction, t, tickerCell)
-					case 5:
-						// Asset Name
-						return c.handleTransactionCell(ptrTransaction, t, assetNameCell)
-					case 6:
-						// Transaction Type
-						return c.handleTransactionCell(ptrTransaction, t, trTypeCell)
-					case 7:
-						// Amount
-						return c.handleTransactionCell(ptrTransaction, t, amountCell)
-					case 8:
-						// Comment
-						if c.handleTransactionCell(ptrTransaction, t, commentCell) {
-							c.handleTransactionCell(ptrTransaction, t, validCell)
-						} else {
-							return false
-						}
-					}
-
-					return true
-				})
-			})
-		} else if title == "Part 4b. Transactions" {
-			section := s.Parent().Parent()
-			trs := section.Find("div.table-responsive table.table tbody tr")
-			regTransactions = make([]Transaction, trs.Length())
-			trs.Each(func(u int, r *goquery.Selection) {
-				var regTransaction *Transaction = &regTransactions[u]
-				tds := r.ChildrenFiltered("td")
-				if tds.Length() != 9 {
-					return
-				}
+	if err != nil {
+		return AnnualDisclosure{}, withReportID(err, result.ReportID)
+	}
 
-				tds.EachWithBreak(func(j int, t *goquery.Selection) bool {
-
-					switch j {
-					case 0:
-						// whitespace
-					case 1:
-						// Transaction Number
-					case 2:
-						// Owner
-						return c.handleTransactionCell(regTransaction, t, ownerCell)
-					case 3:
-						// Ticker
-						return c.handleTransactionCell(regTransaction, t, tickerCell)
-					case 4:
-						// Asset Name
-						return c.handleTransactionCell(regTransaction, t, assetNameCell)
-					case 5:
-						// Transaction Type
-						return c.handleTransactionCell(regTransaction, t, trTypeCell)
-					case 6:
-						// Transaction Date
-						return c.handleTransactionCell(regTransaction, t, trDateCell)
-					case 7:
-						// Amount
-						return c.handleTransactionCell(regTransaction, t, amountCell)
-					case 8:
-						// Comment
-						if c.handleTransactionCell(regTransaction, t, commentCell) {
-							c.handleTransactionCell(regTransaction, t, validCell)
-						} else {
-							return false
-						}
-					}
-
-					return true
-				})
-			})
-		}
-	})
+	return report, nil
+}
+
+// fetchDocument fetches a report page with getReport and parses it as an html document
+func (c *EFDClient) fetchDocument(ctx context.Context, result SearchResult, u *url.URL) (*goquery.Document, error) {
+	resp, err := c.getReport(ctx, u)
+	if err != nil {
+		return nil, err
+	}
 
-	totalTransactions = append(ptrTransactions, regTransactions...)
+	defer resp.Body.Close()
 
-	totalFiltered := totalTransactions[:0]
-	for _, record := range totalTransactions {
-		if record.Valid {
-			totalFiltered = append(totalFiltered, record)
-		}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	return totalFiltered, nil
+	err = c.archiveResponse(ctx, result.ReportID, resp, body)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, &ParseError{ReportID: result.ReportID, Section: "document", Row: -1, Err: err}
+	}
+
+	return doc, nil
 }
 
 // HandlePaperSearchResult takes a SearchResult struct and collects the page URLs from the scanned paper
 func (c *EFDClient) HandlePaperSearchResult(result SearchResult) (PaperReport, error) {
-	var paperReport PaperReport
-	var err error
+	return c.HandlePaperSearchResultContext(context.Background(), result)
+}
 
-	if !c.authed {
-		err = c.AcceptDisclaimer()
-		if err != nil {
-			return paperReport, err
-		}
-	}
+// HandlePaperSearchResultContext is HandlePaperSearchResult with a context controlling cancellation of the request
+func (c *EFDClient) HandlePaperSearchResultContext(ctx context.Context, result SearchResult) (PaperReport, error) {
+	var diag ParseReport
+	return c.handlePaperSearchResult(ctx, result, &diag)
+}
 
+// handlePaperSearchResult fetches the print page of a paper report, recording any page images which were skipped in diag
+func (c *EFDClient) handlePaperSearchResult(ctx context.Context, result SearchResult, diag *ParseReport) (PaperReport, error) {
 	// We do this earlier, but just in case I guess?
-	fileURL := result.FileURL
+	// The URL is copied as the SearchResult may be shared with other goroutines
+	fileURL := *result.FileURL
 	fileURL.Path = strings.Replace(fileURL.Path, "view", "print", 1)
 
-	resp, err := c.client.Get(fileURL.String())
-	if err != nil || resp.StatusCode == http.StatusForbidden {
-		c.authed = false
-		return paperReport, err
+	doc, err := c.fetchDocument(ctx, result, &fileURL)
+	if err != nil {
+		return PaperReport{}, err
 	}
 
-	defer resp.Body.Close()
+	paperReport := c.parsePaperDocument(doc, &fileURL, diag)
+	paperReport.ReportID = result.ReportID
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	err = c.checkStrict(diag)
 	if err != nil {
-		return paperReport, err
+		return PaperReport{}, withReportID(err, result.ReportID)
+	}
+
+	return paperReport, nil
+}
+
+// parsePaperDocument collects the page image URLs of a scanned paper report, resolving them against base if it is set
+// The ReportID is taken from the last element of the base path, and images without a usable src are recorded in diag
+func (c *EFDClient) parsePaperDocument(doc *goquery.Document, base *url.URL, diag *ParseReport) PaperReport {
+	var paperReport PaperReport
+
+	if base != nil {
+		paperReport.ReportID = path.Base(base.Path)
 	}
 
 	pages := doc.Find("img.filingImage")
 	paperReport.PageURLs = make([]*url.URL, pages.Length())
 	pages.Each(func(i int, s *goquery.Selection) {
-		var pageURL *url.URL
-
 		pageURLString, exists := s.Attr("src")
-		if !exists {
+		if !exists || pageURLString == "" {
+			diag.skipSelection(paperSection, i, s, "page image has no src")
 			return
 		}
 
-		pageURL, err = url.Parse(pageURLString)
+		pageURL, err := url.Parse(pageURLString)
 		if err != nil {
+			diag.skipSelection(paperSection, i, s, "could not parse page image URL")
 			return
 		}
 
+		if base != nil {
+			pageURL = base.ResolveReference(pageURL)
+		}
+
 		paperReport.PageURLs[i] = pageURL
-		return
 	})
 
-	return paperReport, nil
+	return paperReport
 }
 
-func (c EFDClient) handleTransactionCell(transaction *Transaction, t *goquery.Selection, ct cellType) bool {
+func (c *EFDClient) handleTransactionCell(transaction *Transaction, t *goquery.Selection, ct cellType) bool {
 	switch ct {
 	case trNumCell:
 		// Transaction Number
@@ -642,6 +788,7 @@ func (c EFDClient) handleTransactionCell(transaction *Transaction, t *goquery.Se
 		}
 
 		transaction.Owner = tString
+		transaction.OwnerKind = ParseOwnerKind(tString)
 	case tickerCell:
 		// Ticker
 		tString, err := c.stripHTMLSelection(t)
@@ -674,6 +821,7 @@ func (c EFDClient) handleTransactionCell(transaction *Transaction, t *goquery.Se
 		}
 
 		transaction.AssetType = tString
+		transaction.AssetKind = ParseAssetKind(tString)
 	case trTypeCell:
 		// Transaction Type
 		tString, err := c.removeHTMLSelection(t)
@@ -682,6 +830,7 @@ func (c EFDClient) handleTransactionCell(transaction *Transaction, t *goquery.Se
 		}
 
 		transaction.Type = tString
+		transaction.Kind = ParseTransactionKind(tString)
 	case amountCell:
 		// Amount
 		tString, err := c.removeHTMLSelection(t)
@@ -690,6 +839,16 @@ func (c EFDClient) handleTransactionCell(transaction *Transaction, t *goquery.Se
 		}
 
 		transaction.Amount = tString
+
+		// Amounts which cannot be parsed keep the raw string with AmountValid unset
+		amount, err := ParseAmount(tString)
+		if err == nil {
+			transaction.AmountMin = amount.Min
+			transaction.AmountMax = amount.Max
+			transaction.AmountOpenEnded = amount.OpenEnded
+			transaction.AmountBand = amount.Band
+			transaction.AmountValid = true
+		}
 	case commentCell:
 		// Comment
 		tString, err := c.removeHTMLSelection(t)
@@ -699,8 +858,11 @@ func (c EFDClient) handleTransactionCell(transaction *Transaction, t *goquery.Se
 
 		transaction.Comment = tString
 	case validCell:
-		// Set Valid
+		// Set Valid, and parse option terms now that every other cell has been handled
 		transaction.Valid = true
+		if isOption(transaction) {
+			transaction.Option = c.parseOptionDetails(transaction)
+		}
 	case ignoreCell:
 		// NOP
 	}
@@ -710,7 +872,7 @@ func (c EFDClient) handleTransactionCell(transaction *Transaction, t *goquery.Se
 
 // trimHTMLSelection takes a goquery selection and retrieves the innerHTML,
 // then filters out newlines and whitespace from either end
-func (c EFDClient) trimHTMLSelection(s *goquery.Selection) (string, error) {
+func (c *EFDClient) trimHTMLSelection(s *goquery.Selection) (string, error) {
 	htmlString, err := s.Html()
 	if err != nil {
 		return "", err
@@ -721,7 +883,7 @@ func (c EFDClient) trimHTMLSelection(s *goquery.Selection) (string, error) {
 
 // stripHTMLSelection is a more aggressive whitespace remover
 // It replaces all consecutive whitespace with a single space
-func (c EFDClient) stripHTMLSelection(s *goquery.Selection) (string, error) {
+func (c *EFDClient) stripHTMLSelection(s *goquery.Selection) (string, error) {
 	htmlString, err := c.trimHTMLSelection(s)
 	if err != nil {
 		return "", err
@@ -733,7 +895,7 @@ func (c EFDClient) stripHTMLSelection(s *goquery.Selection) (string, error) {
 
 // removeHTMLSelection goes a step further than strip and removed all tag-likes
 // This is very unsophisticated, but works for trivial cases
-func (c EFDClient) removeHTMLSelection(s *goquery.Selection) (string, error) {
+func (c *EFDClient) removeHTMLSelection(s *goquery.Selection) (string, error) {
 	htmlString, err := s.Html()
 	if err != nil {
 		return "", err
@@ -748,7 +910,7 @@ func (c EFDClient) removeHTMLSelection(s *goquery.Selection) (string, error) {
 
 // genCSRFToken generates a token for use with the /search/report/data endpoint
 // These tokens are 64 characters alphanumeric including upper and lowercase alphabet
-func (c EFDClient) genCSRFToken() string {
+func (c *EFDClient) genCSRFToken() string {
 	b := make([]rune, 64)
 	for i := range b {
 		b[i] = csrfCharset[rand.Intn(len(csrfCharset))]
@@ -758,24 +920,23 @@ func (c EFDClient) genCSRFToken() string {
 
 // parseCSRFToken parses the `csrfmiddlewaretoken` field from pages with form data
 // On success, the token string will be returned
-func (c EFDClient) parseCSRFToken(url *url.URL) (string, error) {
+func (c *EFDClient) parseCSRFToken(ctx context.Context, client *http.Client, url *url.URL) (string, error) {
 	var csrftoken string = ""
 
-	req, err := http.NewRequest("GET", url.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url.String(), nil)
 	if err != nil {
 		return csrftoken, err
 	}
 
 	req.Header.Add("User-Agent", c.userAgent)
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(ctx, client, req, nil)
 	if err != nil {
 		return "", err
 	}
 
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return "", errors.New("Request returned non-200 status code")
+	if resp.StatusCode != http.StatusOK {
+		return "", newHTTPStatusError(resp)
 	}
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
@@ -788,15 +949,105 @@ func (c EFDClient) parseCSRFToken(url *url.URL) (string, error) {
 	})
 
 	if csrftoken == "" {
-		return "", errors.New("Failed to parse csrftoken")
+		return "", ErrCSRFTokenNotFound
 	}
 
 	return csrftoken, nil
 }
 
+// getReport fetches a report page using the session client, accepting the disclaimer first if needed
+// If efdsearch indicates the session has expired, the disclaimer is accepted again and the request retried once
+// A *ReauthError is returned if the session cannot be re-established
+func (c *EFDClient) getReport(ctx context.Context, u *url.URL) (*http.Response, error) {
+	generation, err := c.ensureSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.getContext(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.sessionExpired(resp) {
+		if resp.StatusCode != http.StatusOK {
+			return nil, newHTTPStatusError(resp)
+		}
+
+		return resp, nil
+	}
+
+	// If another goroutine has already refreshed the session since we made our request, this reuses it
+	err = c.refreshSession(ctx, generation)
+	if err != nil {
+		return nil, &ReauthError{URL: u.String(), Err: err}
+	}
+
+	resp, err = c.getContext(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.sessionExpired(resp) {
+		c.mu.Lock()
+		c.authed = false
+		c.mu.Unlock()
+
+		return nil, &ReauthError{URL: u.String(), Err: ErrSessionExpired}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPStatusError(resp)
+	}
+
+	return resp, nil
+}
+
+// sessionExpired checks a buffered response for signs that efdsearch no longer recognises our session
+// This is either a 403, a redirect back to the disclaimer, or the disclaimer form served in place of the page
+func (c *EFDClient) sessionExpired(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusForbidden {
+		return true
+	}
+
+	if resp.Request != nil && resp.Request.URL.Path == c.homeURL.Path {
+		return true
+	}
+
+	return c.servesDisclaimer(resp)
+}
+
+// servesDisclaimer checks whether a buffered response body contains the disclaimer form
+func (c *EFDClient) servesDisclaimer(resp *http.Response) bool {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return bytes.Contains(body, []byte(`name="prohibition_agreement"`))
+}
+
+// getContext issues a GET request for the provided URL using the session client
+func (c *EFDClient) getContext(ctx context.Context, u *url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("User-Agent", c.userAgent)
+
+	c.mu.RLock()
+	client := c.client
+	c.mu.RUnlock()
+
+	return c.do(ctx, client, req, nil)
+}
+
 // parseAnchor takes in a string and attempts to parse it as if it were an <a> anchor tag
 // On success it returns the structured contents of the field
-func (c EFDClient) parseAnchor(tag string) (anchorData, error) {
+func (c *EFDClient) parseAnchor(tag string) (anchorData, error) {
 	var contents anchorData
 
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(tag))
@@ -814,7 +1065,7 @@ func (c EFDClient) parseAnchor(tag string) (anchorData, error) {
 
 // parseHTMLAnchor takes in an html node and attempts to parse out its contents
 // On success it returns the structured contents of the field
-func (c EFDClient) parseHTMLAnchor(anchor *html.Node) (anchorData, error) {
+func (c *EFDClient) parseHTMLAnchor(anchor *html.Node) (anchorData, error) {
 	var contents anchorData
 
 	if anchor.Type != html.ElementNode || anchor.Data != "a" {
@@ -832,7 +1083,7 @@ func (c EFDClient) parseHTMLAnchor(anchor *html.Node) (anchorData, error) {
 }
 
 // findAttributes iterates over an Attribute slice and attempts to find the corresponding key
-func (c EFDClient) findAttributes(attrs []html.Attribute, key string) (string, error) {
+func (c *EFDClient) findAttributes(attrs []html.Attribute, key string) (string, error) {
 	for _, attr := range attrs {
 		if attr.Key == key {
 			return attr.Val, nil
@@ -843,9 +1094,15 @@ func (c EFDClient) findAttributes(attrs []html.Attribute, key string) (string, e
 }
 
 // clearClient initializes an empty cookiejar and http client. This should be run to clear all client context.
+// Requests already in flight continue to use the previous client and its cookies
 func (c *EFDClient) clearClient() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	// Should be safe to run this multiple times
 	// There is no cookiejar.Clear type method so we need to create a new one to empty it out
 	c.jar, _ = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
-	c.client = &http.Client{Jar: c.jar}
+	c.client = c.opts.newHTTPClient(c.transport, c.jar)
+	c.authed = false
+	c.generation++
 }
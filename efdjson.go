@@ -12,8 +12,12 @@ type JSONURL struct {
 	URL *url.URL
 }
 
-// MarshalJSON implement json marshalling for the URL type
+// MarshalJSON implement json marshalling for the URL type, writing null for a nil URL
 func (j JSONURL) MarshalJSON() ([]byte, error) {
+	if j.URL == nil {
+		return []byte("null"), nil
+	}
+
 	s := j.URL.String()
 
 	return json.Marshal(s)
@@ -37,16 +41,22 @@ func (j JSONURL) UnmarshalJSON(b []byte) error {
 }
 
 // ReportJson is a combined format for Transaction and SearchResult for JSON serialization
+// Annual reports write their Part 4a and 4b transactions to Transactions, with every part of the report also
+// written under Annual
 type ReportJson struct {
-	FirstName     string        `json:"firstname"`
-	LastName      string        `json:"lastname"`
-	ReportName    string        `json:"reportname"`
-	ReportURL     JSONURL       `json:"reporturl,string"`
-	DateSubmitted time.Time     `json:"datesubmitted"`
-	ReportFormat  ReportFormat  `json:"reportformat"`
-	ReportID      string        `json:"reportid"`
-	Transactions  []Transaction `json:"transactions"`
-	Pages         []JSONURL     `json:"pages"`
+	FirstName     string            `json:"firstname"`
+	LastName      string            `json:"lastname"`
+	ReportName    string            `json:"reportname"`
+	Title         ReportTitle       `json:"title"`
+	ReportURL     JSONURL           `json:"reporturl,string"`
+	DateSubmitted time.Time         `json:"datesubmitted"`
+	ReportFormat  ReportFormat      `json:"reportformat"`
+	ReportID      string            `json:"reportid"`
+	Transactions  []Transaction     `json:"transactions"`
+	Annual        *AnnualDisclosure `json:"annual,omitempty"`
+	Extension     *ExtensionNotice  `json:"extension,omitempty"`
+	Pages         []JSONURL         `json:"pages"`
+	Diagnostics   *ParseReport      `json:"diagnostics,omitempty"`
 }
 
 // ReportToJson takes a SearchResult object and results array, then marshals it into a JSON byte array
@@ -56,14 +66,20 @@ func ReportToJson(result SearchResult, parsedReport ParsedReport) ([]byte, error
 	ptrj.FirstName = result.FirstName
 	ptrj.LastName = result.LastName
 	ptrj.ReportName = result.ReportName
+	ptrj.Title = result.Title
 	ptrj.ReportFormat = result.ReportFormat
 	ptrj.ReportURL.URL = result.FileURL
 	ptrj.DateSubmitted = result.DateSubmitted
 	ptrj.ReportID = result.ReportID
 
 	switch ptrj.ReportFormat {
-	case AnnualFormat, PTRFormat:
+	case AnnualFormat:
+		ptrj.Transactions = parsedReport.Transactions
+		ptrj.Annual = &parsedReport.Annual
+	case PTRFormat:
 		ptrj.Transactions = parsedReport.Transactions
+	case DueDateExtensionFormat:
+		ptrj.Extension = &parsedReport.Extension
 	case PaperFormat:
 		ptrj.Pages = make([]JSONURL, len(parsedReport.Pages.PageURLs))
 		for i, page := range parsedReport.Pages.PageURLs {
@@ -71,6 +87,10 @@ func ReportToJson(result SearchResult, parsedReport ParsedReport) ([]byte, error
 		}
 	}
 
+	if !parsedReport.Diagnostics.Empty() {
+		ptrj.Diagnostics = &parsedReport.Diagnostics
+	}
+
 	out, err := json.Marshal(ptrj)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,742 @@
+// Package efdtest provides an in-process fake of the efd search site for exercising an EFDClient in tests
+package efdtest
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"strings"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+)
+
+// Report IDs of the reports returned by Fixtures
+const (
+	PTRFixtureID       = "7bd0e0a7-0bf8-4d45-a0d1-5f2d8bd7e5a1"
+	AnnualFixtureID    = "2f5c8e39-7d0a-4b0f-9c43-2a6f1a9e6c11"
+	PaperFixtureID     = "5a1d9c0e-3b7f-4e2a-8c55-0d4e6b2f7a93"
+	ExtensionFixtureID = "c3e4b9f1-6a2d-4c8e-b7f0-9e1a5d3c2b84"
+
+	// PTRAmendmentFixtureID amends the PTR fixture, changing the amount of one transaction,
+	// removing another, and adding a third
+	PTRAmendmentFixtureID = "e8b1f0c2-4d5a-4f3e-9a6b-1c2d3e4f5a6b"
+)
+
+// PaperFixturePages is the number of page images in the paper fixture
+const PaperFixturePages = 3
+
+// Fixtures returns a representative report of each format handled by EFDClient, along with an amendment of the PTR
+// The report bodies mirror the markup served by efdsearch
+func Fixtures() []Report {
+	paperFiles := make(map[string]File)
+	paperPages := ""
+	for i := 1; i <= PaperFixturePages; i++ {
+		p := fmt.Sprintf("/media/public/%s/page_%d.gif", PaperFixtureID, i)
+		paperFiles[p] = File{ContentType: "image/gif", Body: pageImage(i)}
+		paperPages += fmt.Sprintf("\n      <img class=\"filingImage\" src=\"%s\" alt=\"Filing Image\">", p)
+	}
+
+	return []Report{
+		{
+			ID:            PTRFixtureID,
+			FirstName:     "Jane",
+			LastName:      "Doe",
+			FilerType:     efd.SenatorFiler,
+			State:         "VA",
+			ReportType:    efd.PeriodicTransactionReport,
+			ReportFormat:  efd.PTRFormat,
+			ReportName:    "Periodic Transaction Report for 03/12/2020",
+			DateSubmitted: date(2020, time.March, 12),
+			Body:          ptrPage,
+		},
+		{
+			ID:            AnnualFixtureID,
+			FirstName:     "Jane",
+			LastName:      "Doe",
+			FilerType:     efd.SenatorFiler,
+			State:         "VA",
+			ReportType:    efd.AnnualReport,
+			ReportFormat:  efd.AnnualFormat,
+			ReportName:    "Annual Report for CY 2019",
+			DateSubmitted: date(2020, time.May, 15),
+			Body:          annualPage,
+		},
+		{
+			ID:            PaperFixtureID,
+			FirstName:     "John",
+			LastName:      "Roe",
+			FilerType:     efd.SenatorFiler,
+			State:         "OH",
+			ReportType:    efd.PeriodicTransactionReport,
+			ReportFormat:  efd.PaperFormat,
+			ReportName:    "Periodic Transaction Report",
+			DateSubmitted: date(2012, time.August, 1),
+			Body:          fmt.Sprintf(paperPage, paperPages),
+			Files:         paperFiles,
+		},
+		{
+			ID:            ExtensionFixtureID,
+			FirstName:     "John",
+			LastName:      "Roe",
+			FilerType:     efd.SenatorFiler,
+			State:         "OH",
+			ReportType:    efd.DueDateExtensionReport,
+			ReportFormat:  efd.DueDateExtensionFormat,
+			ReportName:    "Due Date Extension",
+			DateSubmitted: date(2020, time.May, 14),
+			Body:          extensionPage,
+		},
+		{
+			ID:            PTRAmendmentFixtureID,
+			FirstName:     "Jane",
+			LastName:      "Doe",
+			FilerType:     efd.SenatorFiler,
+			State:         "VA",
+			ReportType:    efd.PeriodicTransactionReport,
+			ReportFormat:  efd.PTRFormat,
+			ReportName:    "Periodic Transaction Report for 03/12/2020 (Amendment 1)",
+			DateSubmitted: date(2020, time.March, 20),
+			Body:          ptrAmendmentPage(),
+		},
+	}
+}
+
+// ptrAmendmentPage derives the amended PTR from the original
+func ptrAmendmentPage() string {
+	return strings.NewReplacer(
+		"Filed 03/12/2020 @ 4:30 PM", "Filed 03/20/2020 @ 10:05 AM",
+		"<td>$50,001 - $100,000</td>", "<td>$100,001 - $250,000</td>",
+		ptrExchangeRow, ptrAmendedRow,
+	).Replace(ptrPage)
+}
+
+// date is a shorthand for a UTC midnight time
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// pageImage generates a small gif standing in for a scanned paper page
+// Each page is shaded differently so pages can be told apart
+func pageImage(page int) []byte {
+	shade := uint8(255 - 16*page)
+	palette := color.Palette{color.White, color.Gray{Y: shade}, color.Black}
+
+	img := image.NewPaletted(image.Rect(0, 0, 17, 22), palette)
+	for y := 0; y < 22; y++ {
+		for x := 0; x < 17; x++ {
+			switch {
+			case y == 2 && x >= 2 && x < 2+page:
+				img.SetColorIndex(x, y, 2)
+			case y > 4 && x > 1 && x < 15:
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	gif.Encode(&buf, img, nil)
+
+	return buf.Bytes()
+}
+
+const homePage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>eFD: Home</title>
+</head>
+<body>
+  <div class="container">
+    <h1>Financial Disclosure Search</h1>
+    <form method="POST" action="/search/home/" id="agreement_form">
+      <input type="hidden" name="csrfmiddlewaretoken" value="%s">
+      <div class="form-check">
+        <input class="form-check-input" type="checkbox" value="1" name="prohibition_agreement" id="agree_statement">
+        <label class="form-check-label" for="agree_statement">
+          I understand the prohibitions on obtaining and use of financial disclosure reports.
+        </label>
+      </div>
+      <button type="submit" class="btn btn-primary">Search Reports</button>
+    </form>
+  </div>
+</body>
+</html>
+`
+
+const errorPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%d %s</title>
+</head>
+<body>
+  <h1>The service is temporarily unavailable</h1>
+</body>
+</html>
+`
+
+const searchPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>eFD: Search</title>
+</head>
+<body>
+  <div class="container">
+    <h1>Search Reports</h1>
+    <table class="table table-striped" id="filedReports"></table>
+  </div>
+</body>
+</html>
+`
+
+const ptrPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>eFD: Print Periodic Transaction Report</title>
+</head>
+<body>
+  <div class="container">
+    <h1 class="mb-2">Periodic Transaction Report for 03/12/2020</h1>
+    <h2 class="filedReport">The Honorable Jane Doe (Doe, Jane)</h2>
+    <p class="muted font-weight-bold">Filed 03/12/2020 @ 4:30 PM</p>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Transactions</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Transaction Date</th>
+                <th scope="col">Owner</th>
+                <th scope="col">Ticker</th>
+                <th scope="col">Asset Name</th>
+                <th scope="col">Asset Type</th>
+                <th scope="col">Type</th>
+                <th scope="col">Amount</th>
+                <th scope="col">Comment</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>03/10/2020</td>
+                <td>Spouse</td>
+                <td><a href="https://finance.yahoo.com/quote/AAPL" target="_blank">AAPL</a></td>
+                <td>Apple Inc.</td>
+                <td>Stock</td>
+                <td>Sale (Full)</td>
+                <td>$1,001 - $15,000</td>
+                <td>--</td>
+              </tr>
+              <tr>
+                <td>2</td>
+                <td>03/10/2020</td>
+                <td>Self</td>
+                <td><a href="https://finance.yahoo.com/quote/MSFT" target="_blank">MSFT</a></td>
+                <td>Microsoft Corporation
+                  <div class="text-muted">
+                    <em>Option Type:</em> Call<br>
+                    <em>Strike price:</em> $150.00<br>
+                    <em>Expires:</em> 06/19/2020
+                  </div>
+                </td>
+                <td>Stock Option</td>
+                <td>Purchase</td>
+                <td>$15,001 - $50,000</td>
+                <td>10 contracts</td>
+              </tr>
+              <tr>
+                <td>3</td>
+                <td>03/11/2020</td>
+                <td>Joint</td>
+                <td>--</td>
+                <td>Fairfax County VA Public Improvement Bond 4.00% 10/01/2030</td>
+                <td>Municipal Security</td>
+                <td>Sale (Partial)</td>
+                <td>$50,001 - $100,000</td>
+                <td>--</td>
+              </tr>
+` + ptrExchangeRow + `            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+  </div>
+</body>
+</html>
+`
+
+// ptrExchangeRow is the last transaction of the PTR fixture, which the amendment replaces with ptrAmendedRow
+const ptrExchangeRow = `              <tr>
+                <td>4</td>
+                <td>03/11/2020</td>
+                <td>Spouse</td>
+                <td><a href="https://finance.yahoo.com/quote/XOM" target="_blank">XOM</a></td>
+                <td>Exxon Mobil Corporation</td>
+                <td>Stock</td>
+                <td>Exchange</td>
+                <td>Over $50,000,000</td>
+                <td>--</td>
+              </tr>
+`
+
+const ptrAmendedRow = `              <tr>
+                <td>4</td>
+                <td>02/03/2020</td>
+                <td>Self</td>
+                <td><a href="https://finance.yahoo.com/quote/NVDA" target="_blank">NVDA</a></td>
+                <td>NVIDIA Corporation</td>
+                <td>Stock</td>
+                <td>Purchase</td>
+                <td>$1,001 - $15,000</td>
+                <td>Omitted from original filing</td>
+              </tr>
+`
+
+const annualPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>eFD: Print Annual Report</title>
+</head>
+<body>
+  <div class="container">
+    <h1 class="mb-2">Annual Report for CY 2019</h1>
+    <h2 class="filedReport">The Honorable Jane Doe (Doe, Jane)</h2>
+    <p class="muted font-weight-bold">Filed 05/15/2020 @ 11:02 AM</p>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 1. Charitable Contributions</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Date</th>
+                <th scope="col">Activity</th>
+                <th scope="col">Amount</th>
+                <th scope="col">Paid By</th>
+                <th scope="col">Paid To</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>10/14/2019</td>
+                <td>Speech</td>
+                <td>$2,000.00</td>
+                <td>Rotary Club (Richmond, VA)</td>
+                <td>Food Bank of Central Virginia (Richmond, VA)</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 2. Earned and Non-Investment Income</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Who Was Paid</th>
+                <th scope="col">Type</th>
+                <th scope="col">Who Paid</th>
+                <th scope="col">Amount Paid</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>Spouse</td>
+                <td>Salary</td>
+                <td>Arlington Public Schools (Arlington, VA)</td>
+                <td>N/A</td>
+              </tr>
+              <tr>
+                <td>2</td>
+                <td>Self</td>
+                <td>Book Royalties</td>
+                <td>Penguin Random House (New York, NY)</td>
+                <td>$12,500.00</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 3. Assets</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Asset</th>
+                <th scope="col">Asset Type</th>
+                <th scope="col">Owner</th>
+                <th scope="col">Value</th>
+                <th scope="col">Income Type</th>
+                <th scope="col">Income</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>Apple Inc. <div class="text-muted">Ticker: AAPL</div></td>
+                <td>Stock</td>
+                <td>Spouse</td>
+                <td>$15,001 - $50,000</td>
+                <td>Dividends</td>
+                <td>$201 - $1,000</td>
+              </tr>
+              <tr>
+                <td>2</td>
+                <td>Vanguard Total Stock Market Index Fund Admiral Shares</td>
+                <td>Mutual Funds</td>
+                <td>Joint</td>
+                <td>$100,001 - $250,000</td>
+                <td>Capital Gains, Dividends</td>
+                <td>$2,501 - $5,000</td>
+              </tr>
+              <tr>
+                <td>3</td>
+                <td>Virginia College Savings Plan 529</td>
+                <td>Education Savings Plans</td>
+                <td>Dependent Child</td>
+                <td>$50,001 - $100,000</td>
+                <td>Excepted/Blind Trust</td>
+                <td>None (or less than $201)</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 4a. Periodic Transaction Report Summary</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col"></th>
+                <th scope="col">#</th>
+                <th scope="col">Transaction Date</th>
+                <th scope="col">Owner</th>
+                <th scope="col">Ticker</th>
+                <th scope="col">Asset Name</th>
+                <th scope="col">Transaction Type</th>
+                <th scope="col">Amount</th>
+                <th scope="col">Comment</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td></td>
+                <td>1</td>
+                <td>08/05/2019</td>
+                <td>Spouse</td>
+                <td><a href="https://finance.yahoo.com/quote/AAPL" target="_blank">AAPL</a></td>
+                <td>Apple Inc.</td>
+                <td>Purchase</td>
+                <td>$1,001 - $15,000</td>
+                <td>--</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 4b. Transactions</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col"></th>
+                <th scope="col">#</th>
+                <th scope="col">Owner</th>
+                <th scope="col">Ticker</th>
+                <th scope="col">Asset Name</th>
+                <th scope="col">Transaction Type</th>
+                <th scope="col">Transaction Date</th>
+                <th scope="col">Amount</th>
+                <th scope="col">Comment</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td></td>
+                <td>1</td>
+                <td>Joint</td>
+                <td>--</td>
+                <td>Vanguard Total Stock Market Index Fund Admiral Shares</td>
+                <td>Sale (Partial)</td>
+                <td>11/20/2019</td>
+                <td>$15,001 - $50,000</td>
+                <td>Rebalancing</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 5. Gifts</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Date</th>
+                <th scope="col">Recipient</th>
+                <th scope="col">Gift</th>
+                <th scope="col">Value</th>
+                <th scope="col">From</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>12/20/2019</td>
+                <td>Self</td>
+                <td>Framed print</td>
+                <td>$450.00</td>
+                <td>John Smith (Norfolk, VA)</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 6. Travel</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Date(s)</th>
+                <th scope="col">Traveler(s)</th>
+                <th scope="col">Itinerary</th>
+                <th scope="col">Purpose</th>
+                <th scope="col">Reimbursed By</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>02/07/2019 - 02/09/2019</td>
+                <td>Self, Spouse</td>
+                <td>Washington, DC - Aspen, CO - Washington, DC</td>
+                <td>Panelist, Energy Policy Forum</td>
+                <td>Aspen Institute (Washington, DC)</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 7. Liabilities</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Year Incurred</th>
+                <th scope="col">Debtor</th>
+                <th scope="col">Type</th>
+                <th scope="col">Points</th>
+                <th scope="col">Term</th>
+                <th scope="col">Rate</th>
+                <th scope="col">Amount</th>
+                <th scope="col">Creditor</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>2015</td>
+                <td>Joint</td>
+                <td>Mortgage on Personal Residence</td>
+                <td>N/A</td>
+                <td>30 Years</td>
+                <td>3.625%</td>
+                <td>$250,001 - $500,000</td>
+                <td>Wells Fargo (Des Moines, IA)</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 8. Positions</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Position Dates</th>
+                <th scope="col">Position Held</th>
+                <th scope="col">Entity</th>
+                <th scope="col">Entity Type</th>
+                <th scope="col">Comment</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>Jan 2012 - Present</td>
+                <td>Board Member</td>
+                <td>Richmond Symphony (Richmond, VA)</td>
+                <td>Non-Profit</td>
+                <td>Uncompensated</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 9. Agreements</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Date</th>
+                <th scope="col">Parties Involved</th>
+                <th scope="col">Type</th>
+                <th scope="col">Status and Terms</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>06/2008</td>
+                <td>Self, Commonwealth of Virginia Retirement System (Richmond, VA)</td>
+                <td>Pension</td>
+                <td>Defined benefit plan, no further contributions</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Part 10. Compensation in Excess of $5,000 Paid by One Source</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <thead>
+              <tr class="header">
+                <th scope="col">#</th>
+                <th scope="col">Source (Name and Address)</th>
+                <th scope="col">Brief Description of Duties</th>
+              </tr>
+            </thead>
+            <tbody>
+              <tr>
+                <td>1</td>
+                <td>Hunton &amp; Williams LLP (Richmond, VA)</td>
+                <td>Legal services</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+  </div>
+</body>
+</html>
+`
+
+const paperPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>eFD: Print Paper Report</title>
+</head>
+<body>
+  <div class="container">
+    <h1 class="mb-2">Periodic Transaction Report</h1>
+    <h2 class="filedReport">The Honorable John Roe (Roe, John)</h2>
+    <div class="filingImages">%s
+    </div>
+  </div>
+</body>
+</html>
+`
+
+const extensionPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>eFD: Print Due Date Extension</title>
+</head>
+<body>
+  <div class="container">
+    <h1 class="mb-2">Due Date Extension</h1>
+    <h2 class="filedReport">The Honorable John Roe (Roe, John)</h2>
+    <p class="muted font-weight-bold">Filed 05/14/2020 @ 9:12 AM</p>
+    <section class="card mb-2">
+      <div class="card-body">
+        <h3 class="h4">Extension Details</h3>
+        <div class="table-responsive">
+          <table class="table table-striped">
+            <tbody>
+              <tr>
+                <th scope="row">Report Being Extended</th>
+                <td>Annual Report for CY 2019</td>
+              </tr>
+              <tr>
+                <th scope="row">Original Due Date</th>
+                <td>05/15/2020</td>
+              </tr>
+              <tr>
+                <th scope="row">Extension Length</th>
+                <td>90 Days</td>
+              </tr>
+              <tr>
+                <th scope="row">New Due Date</th>
+                <td>08/13/2020</td>
+              </tr>
+              <tr>
+                <th scope="row">Filer</th>
+                <td>John Roe</td>
+              </tr>
+              <tr>
+                <th scope="row">Filer Type</th>
+                <td>Senator</td>
+              </tr>
+              <tr>
+                <th scope="row">State</th>
+                <td>OH</td>
+              </tr>
+            </tbody>
+          </table>
+        </div>
+      </div>
+    </section>
+  </div>
+</body>
+</html>
+`
@@ -0,0 +1,595 @@
+// Package efdtest provides an in-process fake of the efd search site for exercising an EFDClient in tests
+package efdtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+)
+
+const (
+	homePath       = "/search/home/"
+	searchPath     = "/search/"
+	searchDataPath = "/search/report/data/"
+	viewPath       = "/search/view/"
+	printPath      = "/search/print/"
+
+	csrfCookieName    = "csrftoken"
+	sessionCookieName = "sessionid"
+
+	searchDateLayout = "01/02/2006 15:04:05"
+	dateLayout       = "01/02/2006"
+)
+
+// File is a static resource served by the Server, such as a paper report page image
+type File struct {
+	ContentType string
+	Body        []byte
+}
+
+// Report is a single filing made available through the Server
+// It is returned by the search endpoint when it matches the search filters and its Body is served at its view URL
+type Report struct {
+	ID            string
+	FirstName     string
+	LastName      string
+	FilerType     efd.FilerType
+	State         string
+	ReportType    efd.ReportType
+	ReportFormat  efd.ReportFormat
+	ReportName    string
+	DateSubmitted time.Time
+
+	// Body is the HTML document served for the report
+	Body string
+
+	// Files are additional resources served alongside the report, keyed by absolute path
+	Files map[string]File
+}
+
+// Path returns the path the report is linked to from the search results
+func (r Report) Path() string {
+	switch r.ReportFormat {
+	case efd.PTRFormat:
+		return viewPath + "ptr/" + r.ID + "/"
+	case efd.AnnualFormat:
+		return viewPath + "annual/" + r.ID + "/"
+	case efd.PaperFormat:
+		return viewPath + "paper/" + r.ID + "/"
+	case efd.DueDateExtensionFormat:
+		return viewPath + "extension-notice/regular/" + r.ID + "/"
+	}
+
+	return viewPath + "other/" + r.ID + "/"
+}
+
+// Server is a fake efd search site backed by an httptest.Server
+// It emulates the disclaimer form, cookie-gated report views, and the search data endpoint
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	reports  []Report
+	pages    map[string]Report
+	files    map[string]File
+	tokens   map[string]bool
+	sessions map[string]bool
+	failures map[string][]failure
+	requests map[string]int
+	accepted int
+	latency  time.Duration
+	inFlight int
+	peak     int
+}
+
+// failure is a queued failed response
+type failure struct {
+	status     int
+	retryAfter time.Duration
+}
+
+// NewServer starts and returns a Server serving the provided reports
+// Use Fixtures() for a representative set of reports of each format
+// The caller should call Close when finished to shut it down
+func NewServer(reports ...Report) *Server {
+	s := &Server{
+		pages:    make(map[string]Report),
+		files:    make(map[string]File),
+		tokens:   make(map[string]bool),
+		sessions: make(map[string]bool),
+		failures: make(map[string][]failure),
+		requests: make(map[string]int),
+	}
+
+	for _, report := range reports {
+		s.AddReport(report)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(homePath, s.handleHome)
+	mux.HandleFunc(searchDataPath, s.handleSearchData)
+	mux.HandleFunc(searchPath, s.handleSearch)
+	mux.HandleFunc(viewPath, s.handleReport)
+	mux.HandleFunc(printPath, s.handleReport)
+	mux.HandleFunc("/", s.handleFile)
+
+	s.Server = httptest.NewServer(s.injectFailures(mux))
+
+	return s
+}
+
+// EFDClient returns an EFDClient configured to talk to this server
+// Any additional options are applied after the server's base URL and HTTP client
+func (s *Server) EFDClient(opts ...efd.ClientOption) *efd.EFDClient {
+	baseURL, _ := url.Parse(s.URL)
+
+	opts = append([]efd.ClientOption{efd.WithBaseURL(baseURL), efd.WithHTTPClient(s.Client())}, opts...)
+
+	return efd.CreateEFDClient("", "", opts...)
+}
+
+// AddReport makes a report and its files available through the server
+func (s *Server) AddReport(r Report) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reports = append(s.reports, r)
+	sort.SliceStable(s.reports, func(i, j int) bool {
+		return s.reports[i].DateSubmitted.After(s.reports[j].DateSubmitted)
+	})
+
+	s.pages[r.Path()] = r
+	if r.ReportFormat == efd.PaperFormat {
+		s.pages[strings.Replace(r.Path(), "view", "print", 1)] = r
+	}
+
+	for p, f := range r.Files {
+		s.files[p] = f
+	}
+}
+
+// AddFile serves a static resource at the provided absolute path
+func (s *Server) AddFile(path string, f File) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[path] = f
+}
+
+// ExpireSessions invalidates every session which has accepted the disclaimer
+// Subsequent report requests are redirected to the disclaimer until it is accepted again
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions = make(map[string]bool)
+}
+
+// InjectFailures makes the next requests to path fail with the provided status codes, one per request
+// Failed requests are answered with an html error page, as efdsearch does when it is overloaded
+func (s *Server) InjectFailures(path string, statusCodes ...int) {
+	s.InjectFailuresRetryAfter(path, 0, statusCodes...)
+}
+
+// InjectFailuresRetryAfter is InjectFailures with a Retry-After header of retryAfter, in whole seconds,
+// sent with each failure
+func (s *Server) InjectFailuresRetryAfter(path string, retryAfter time.Duration, statusCodes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, status := range statusCodes {
+		s.failures[path] = append(s.failures[path], failure{status: status, retryAfter: retryAfter})
+	}
+}
+
+// Acceptances returns the number of times the disclaimer has been accepted, each starting a new session
+func (s *Server) Acceptances() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.accepted
+}
+
+// Requests returns the number of requests made to path, including any answered with an injected failure
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[path]
+}
+
+// SetLatency delays every response by d, so that concurrent requests overlap
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// MaxInFlight returns the largest number of requests the server has handled at once
+func (s *Server) MaxInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.peak
+}
+
+// injectFailures wraps a handler, counting requests and answering them with any failures queued by InjectFailures
+func (s *Server) injectFailures(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[r.URL.Path]++
+		s.inFlight++
+		if s.inFlight > s.peak {
+			s.peak = s.inFlight
+		}
+		latency := s.latency
+		queued := s.failures[r.URL.Path]
+		var f failure
+		if len(queued) > 0 {
+			f = queued[0]
+			s.failures[r.URL.Path] = queued[1:]
+		}
+		s.mu.Unlock()
+
+		defer func() {
+			s.mu.Lock()
+			s.inFlight--
+			s.mu.Unlock()
+		}()
+
+		time.Sleep(latency)
+
+		if f.status == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if f.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(f.retryAfter/time.Second)))
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(f.status)
+		fmt.Fprintf(w, errorPage, f.status, http.StatusText(f.status))
+	})
+}
+
+// handleHome serves the disclaimer form and processes its submission
+func (s *Server) handleHome(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		// Like Django, an existing csrf cookie is reused rather than rotated on every visit
+		token := s.newToken()
+		if cookie, err := r.Cookie(csrfCookieName); err == nil {
+			token = cookie.Value
+		}
+
+		s.mu.Lock()
+		if !s.tokens[token] {
+			token = s.newToken()
+			s.tokens[token] = true
+		}
+		s.mu.Unlock()
+
+		http.SetCookie(w, &http.Cookie{Name: csrfCookieName, Value: token, Path: "/"})
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, homePage, token)
+	case http.MethodPost:
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		token := r.PostForm.Get("csrfmiddlewaretoken")
+		cookie, err := r.Cookie(csrfCookieName)
+
+		s.mu.Lock()
+		valid := s.tokens[token]
+		s.mu.Unlock()
+
+		if err != nil || cookie.Value != token || !valid {
+			http.Error(w, "Forbidden (CSRF token missing or incorrect.)", http.StatusForbidden)
+			return
+		}
+
+		if r.PostForm.Get("prohibition_agreement") != "1" {
+			http.Redirect(w, r, homePath, http.StatusFound)
+			return
+		}
+
+		session := s.newToken()
+
+		s.mu.Lock()
+		s.sessions[session] = true
+		s.accepted++
+		s.mu.Unlock()
+
+		http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: session, Path: "/", HttpOnly: true})
+		http.Redirect(w, r, searchPath, http.StatusFound)
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSearch serves the search form, which like the real site requires an accepted disclaimer
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != searchPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	if !s.authed(r) {
+		http.Redirect(w, r, homePath+"?next="+url.QueryEscape(r.URL.Path), http.StatusFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, searchPage)
+}
+
+// handleSearchData emulates the paged json search endpoint
+func (s *Server) handleSearchData(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cookie, err := r.Cookie(csrfCookieName)
+	if err != nil || cookie.Value == "" || r.Header.Get("X-CSRFToken") != cookie.Value {
+		http.Error(w, "Forbidden (CSRF token missing or incorrect.)", http.StatusForbidden)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	f, err := parseSearchFilter(r.PostForm)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	var matched []Report
+	for _, report := range s.reports {
+		if f.matches(report) {
+			matched = append(matched, report)
+		}
+	}
+	s.mu.Unlock()
+
+	type searchData struct {
+		Draw            int        `json:"draw"`
+		RecordsTotal    int        `json:"recordsTotal"`
+		RecordsFiltered int        `json:"recordsFiltered"`
+		Data            [][]string `json:"data"`
+		Result          string     `json:"result"`
+	}
+
+	resp := searchData{
+		RecordsTotal:    len(matched),
+		RecordsFiltered: len(matched),
+		Data:            [][]string{},
+		Result:          "ok",
+	}
+
+	for i := f.start; i < len(matched) && i < f.start+f.length; i++ {
+		report := matched[i]
+		link := fmt.Sprintf(`<a href="%s" target="_blank">%s</a>`, report.Path(), html.EscapeString(report.ReportName))
+		resp.Data = append(resp.Data, []string{
+			strings.ToUpper(report.FirstName),
+			strings.ToUpper(report.LastName),
+			fmt.Sprintf("%s, %s (Senator)", report.LastName, report.FirstName),
+			link,
+			report.DateSubmitted.Format(dateLayout),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handleReport serves report views, redirecting to the disclaimer if the session has not accepted it
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	if !s.authed(r) {
+		http.Redirect(w, r, homePath+"?next="+url.QueryEscape(r.URL.Path), http.StatusFound)
+		return
+	}
+
+	s.mu.Lock()
+	report, ok := s.pages[r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, report.Body)
+}
+
+// handleFile serves static resources registered with AddFile or a Report
+func (s *Server) handleFile(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	f, ok := s.files[r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", f.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(f.Body)))
+	w.Write(f.Body)
+}
+
+// authed checks whether the request carries a session cookie which has accepted the disclaimer
+func (s *Server) authed(r *http.Request) bool {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sessions[cookie.Value]
+}
+
+// newToken generates a random token for csrf and session cookies
+func (s *Server) newToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// searchFilter holds the parsed form values of a search data request
+type searchFilter struct {
+	firstName   string
+	lastName    string
+	state       string
+	filerTypes  []efd.FilerType
+	reportTypes []efd.ReportType
+	startTime   time.Time
+	endTime     time.Time
+	start       int
+	length      int
+}
+
+// parseSearchFilter parses search data form values in the format sent by EFDClient
+func parseSearchFilter(form url.Values) (searchFilter, error) {
+	var f searchFilter
+	var err error
+
+	f.firstName = strings.ToLower(form.Get("first_name"))
+	f.lastName = strings.ToLower(form.Get("last_name"))
+	f.state = strings.ToLower(form.Get("senator_state"))
+
+	f.filerTypes, err = parseIntEnumArray(form.Get("filer_types"))
+	if err != nil {
+		return f, fmt.Errorf("invalid filer_types: %v", err)
+	}
+
+	f.reportTypes, err = parseIntEnumArray(form.Get("report_types"))
+	if err != nil {
+		return f, fmt.Errorf("invalid report_types: %v", err)
+	}
+
+	if v := form.Get("submitted_start_date"); v != "" {
+		f.startTime, err = time.Parse(searchDateLayout, v)
+		if err != nil {
+			return f, fmt.Errorf("invalid submitted_start_date: %v", err)
+		}
+	}
+
+	if v := form.Get("submitted_end_date"); v != "" {
+		f.endTime, err = time.Parse(searchDateLayout, v)
+		if err != nil {
+			return f, fmt.Errorf("invalid submitted_end_date: %v", err)
+		}
+	}
+
+	f.start, err = strconv.Atoi(form.Get("start"))
+	if err != nil || f.start < 0 {
+		return f, fmt.Errorf("invalid start: %q", form.Get("start"))
+	}
+
+	f.length, err = strconv.Atoi(form.Get("length"))
+	if err != nil || f.length < 0 {
+		return f, fmt.Errorf("invalid length: %q", form.Get("length"))
+	}
+
+	return f, nil
+}
+
+// matches reports whether a report satisfies every filter that was provided
+func (f searchFilter) matches(r Report) bool {
+	if f.firstName != "" && !strings.HasPrefix(strings.ToLower(r.FirstName), f.firstName) {
+		return false
+	}
+
+	if f.lastName != "" && !strings.HasPrefix(strings.ToLower(r.LastName), f.lastName) {
+		return false
+	}
+
+	if f.state != "" && strings.ToLower(r.State) != f.state {
+		return false
+	}
+
+	if len(f.filerTypes) > 0 && !containsIntEnum(f.filerTypes, r.FilerType) {
+		return false
+	}
+
+	if len(f.reportTypes) > 0 && !containsIntEnum(f.reportTypes, r.ReportType) {
+		return false
+	}
+
+	if !f.startTime.IsZero() && r.DateSubmitted.Before(f.startTime) {
+		return false
+	}
+
+	if !f.endTime.IsZero() && r.DateSubmitted.After(f.endTime) {
+		return false
+	}
+
+	return true
+}
+
+// parseIntEnumArray parses the [1,2] array format used for filer and report types
+func parseIntEnumArray(s string) ([]efd.FilerType, error) {
+	var vals []efd.FilerType
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return vals, nil
+	}
+
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("%q is not an array", s)
+	}
+
+	for _, part := range strings.Split(s[1:len(s)-1], ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		val, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+
+		vals = append(vals, efd.FilerType(val))
+	}
+
+	return vals, nil
+}
+
+// containsIntEnum reports whether val is present in vals
+func containsIntEnum(vals []efd.FilerType, val efd.FilerType) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,64 @@
+package efdtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+func TestSearchPagesAndFilters(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.EFDClient()
+
+	results, err := client.Search(ctx, efd.SearchQuery{PageSize: 2})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(results) != len(efdtest.Fixtures()) {
+		t.Errorf("got %d results across pages, want %d", len(results), len(efdtest.Fixtures()))
+	}
+
+	if got := server.Requests("/search/report/data/"); got != 3 {
+		t.Errorf("got %d search pages, want 3", got)
+	}
+
+	results, err = client.Search(ctx, efd.SearchQuery{
+		LastName:    "Doe",
+		ReportTypes: []efd.ReportType{efd.PeriodicTransactionReport},
+		StartTime:   time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2020, time.March, 12, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(results) != 1 || results[0].ReportID != efdtest.PTRFixtureID || results[0].ReportFormat != efd.PTRFormat {
+		t.Errorf("got %+v, want only the original PTR", results)
+	}
+}
+
+func TestReportsRequireDisclaimer(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + efdtest.Fixtures()[0].Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.Request.URL.Path != "/search/home/" {
+		t.Errorf("unauthenticated report request ended at %s, want the disclaimer", resp.Request.URL.Path)
+	}
+
+	if server.Acceptances() != 0 {
+		t.Errorf("disclaimer accepted without a form submission")
+	}
+}
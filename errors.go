@@ -0,0 +1,114 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors returned by EFDClient, these can be matched with errors.Is
+var (
+	// ErrNotAuthenticated indicates efdsearch did not accept the disclaimer submission
+	ErrNotAuthenticated = errors.New("efd disclaimer was not accepted")
+
+	// ErrSessionExpired indicates efdsearch rejected the session, either with a 403 or by serving the disclaimer
+	ErrSessionExpired = errors.New("efd session expired")
+
+	// ErrUnexpectedContentType indicates a response was not of the expected content type
+	// efdsearch serves html error pages in place of json when it is struggling, so this is usually transient
+	ErrUnexpectedContentType = errors.New("efd response has unexpected content type")
+
+	// ErrSearchFailed indicates the search endpoint returned a result status other than ok
+	ErrSearchFailed = errors.New("efd search endpoint returned error status")
+
+	// ErrInvalidQuery indicates a SearchQuery failed validation
+	ErrInvalidQuery = errors.New("efd invalid search query")
+
+	// ErrInvalidAmount indicates a transaction amount could not be parsed into a range
+	ErrInvalidAmount = errors.New("efd invalid amount")
+
+	// ErrArchiveFailed indicates a raw response could not be saved to the archive Sink
+	ErrArchiveFailed = errors.New("efd failed to archive response")
+
+	// ErrRowSkipped indicates a row could not be parsed while strict parsing is enabled
+	ErrRowSkipped = errors.New("efd row skipped")
+
+	// ErrCSRFTokenNotFound indicates no csrfmiddlewaretoken could be found in the disclaimer form
+	ErrCSRFTokenNotFound = errors.New("efd csrf token not found")
+)
+
+// HTTPStatusError is returned when efdsearch responds with an unexpected HTTP status code
+type HTTPStatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// newHTTPStatusError creates an HTTPStatusError describing the response
+func newHTTPStatusError(resp *http.Response) *HTTPStatusError {
+	e := &HTTPStatusError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+
+	return e
+}
+
+// ParseError is returned when part of a search page or report could not be parsed
+// Row is the zero-based row index within Section, or -1 if the error is not specific to a row
+type ParseError struct {
+	ReportID string
+	Section  string
+	Row      int
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	where := e.Section
+	if e.ReportID != "" {
+		where = e.ReportID + " " + where
+	}
+
+	if e.Row >= 0 {
+		return fmt.Sprintf("parsing %s row %d: %v", where, e.Row, e.Err)
+	}
+
+	return fmt.Sprintf("parsing %s: %v", where, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// withReportID fills in the ReportID of a ParseError if it is not already set
+func withReportID(err error, reportID string) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) && parseErr.ReportID == "" {
+		parseErr.ReportID = reportID
+	}
+
+	return err
+}
+
+// ReauthError is returned when a session expired while fetching a report and could not be re-established
+// Err is either the error from accepting the disclaimer, or ErrSessionExpired if the retried request was rejected again
+type ReauthError struct {
+	URL string
+	Err error
+}
+
+func (e *ReauthError) Error() string {
+	return fmt.Sprintf("re-authenticating for %s: %v", e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ReauthError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,130 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// extensionSection is the section name used for errors and unknown labels in due date extension notices
+const extensionSection = "Extension Details"
+
+// HandleExtensionSearchResult takes a SearchResult struct and parses out the details of a due date extension notice
+func (c *EFDClient) HandleExtensionSearchResult(result SearchResult) (ExtensionNotice, error) {
+	return c.HandleExtensionSearchResultContext(context.Background(), result)
+}
+
+// HandleExtensionSearchResultContext is HandleExtensionSearchResult with a context controlling cancellation of the request
+func (c *EFDClient) HandleExtensionSearchResultContext(ctx context.Context, result SearchResult) (ExtensionNotice, error) {
+	var diag ParseReport
+	return c.handleExtensionSearchResult(ctx, result, &diag)
+}
+
+// handleExtensionSearchResult fetches and parses a due date extension notice, recording skipped rows and labels in diag
+func (c *EFDClient) handleExtensionSearchResult(ctx context.Context, result SearchResult, diag *ParseReport) (ExtensionNotice, error) {
+	doc, err := c.fetchDocument(ctx, result, result.FileURL)
+	if err != nil {
+		return ExtensionNotice{}, err
+	}
+
+	notice, err := c.parseExtensionDocument(doc, diag)
+	if err == nil {
+		err = c.checkStrict(diag)
+	}
+
+	if err != nil {
+		return ExtensionNotice{}, withReportID(err, result.ReportID)
+	}
+
+	return notice, nil
+}
+
+// parseExtensionDocument parses the label and value rows of a due date extension notice
+// Both due dates are required, any other missing rows are left empty
+// Rows which are not a single label and value, and unrecognised labels, are recorded in diag
+func (c *EFDClient) parseExtensionDocument(doc *goquery.Document, diag *ParseReport) (ExtensionNotice, error) {
+	var notice ExtensionNotice
+	var err error
+
+	rows := doc.Find("div.table-responsive table.table tr")
+	rows.EachWithBreak(func(i int, tr *goquery.Selection) bool {
+		th := tr.ChildrenFiltered("th")
+		td := tr.ChildrenFiltered("td")
+		if th.Length() != 1 || td.Length() != 1 {
+			diag.skipSelection(extensionSection, i, tr, "expected a single label and value")
+			return true
+		}
+
+		label := strings.Join(strings.Fields(th.Text()), " ")
+		value := c.cellText(td)
+
+		switch normalizeHeader(label) {
+		case "report being extended", "report":
+			notice.ReportExtended = value
+		case "original due date", "due date":
+			notice.OriginalDueDate, err = c.parseExtensionDate(label, value, i)
+		case "new due date", "extended due date":
+			notice.NewDueDate, err = c.parseExtensionDate(label, value, i)
+		case "extension length", "length of extension":
+			notice.ExtensionLength = value
+			notice.ExtensionDays = parseExtensionDays(value)
+		case "filer", "filer name", "name":
+			notice.Filer = value
+		case "filer type":
+			notice.FilerType = value
+		case "state":
+			notice.State = value
+		default:
+			diag.UnknownColumns = append(diag.UnknownColumns, extensionSection+": "+label)
+		}
+
+		return err == nil
+	})
+
+	if err != nil {
+		return notice, err
+	}
+
+	if notice.OriginalDueDate.IsZero() || notice.NewDueDate.IsZero() {
+		return notice, &ParseError{Section: extensionSection, Row: -1,
+			Err: errors.New("extension notice is missing a due date")}
+	}
+
+	// Derive the length from the dates if the notice did not state it in days
+	if notice.ExtensionDays == 0 {
+		notice.ExtensionDays = int(notice.NewDueDate.Sub(notice.OriginalDueDate).Hours() / 24)
+	}
+
+	return notice, nil
+}
+
+// parseExtensionDate parses the value of a due date row with the client date layout
+func (c *EFDClient) parseExtensionDate(label, value string, row int) (time.Time, error) {
+	t, err := time.Parse(c.dateLayout, value)
+	if err != nil {
+		return t, &ParseError{Section: extensionSection, Row: row, Err: fmt.Errorf("parsing %s: %w", label, err)}
+	}
+
+	return t, nil
+}
+
+// parseExtensionDays returns the number of days in an extension length such as "90 Days", or 0 if it is not in days
+func parseExtensionDays(length string) int {
+	fields := strings.Fields(length)
+	if len(fields) != 2 || !strings.HasPrefix(strings.ToLower(fields[1]), "day") {
+		return 0
+	}
+
+	days, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0
+	}
+
+	return days
+}
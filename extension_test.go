@@ -0,0 +1,87 @@
+package efd_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// withoutRow removes the row with the given label from an extension notice body
+func withoutRow(t *testing.T, body string, label string) string {
+	t.Helper()
+
+	row := regexp.MustCompile(`\s*<tr>\s*<th scope="row">` + label + `</th>\s*<td>[^<]*</td>\s*</tr>`)
+	if !row.MatchString(body) {
+		t.Fatalf("no %s row in the extension notice", label)
+	}
+
+	return row.ReplaceAllString(body, "")
+}
+
+func TestExtensionNoticeFromServer(t *testing.T) {
+	parsed, err := fetchOnly(t, fixture(t, efdtest.ExtensionFixtureID))
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	want := efd.ExtensionNotice{
+		ReportExtended:  "Annual Report for CY 2019",
+		OriginalDueDate: time.Date(2020, time.May, 15, 0, 0, 0, 0, time.UTC),
+		NewDueDate:      time.Date(2020, time.August, 13, 0, 0, 0, 0, time.UTC),
+		ExtensionLength: "90 Days",
+		ExtensionDays:   90,
+		Filer:           "John Roe",
+		FilerType:       "Senator",
+		State:           "OH",
+	}
+
+	if parsed.Extension != want {
+		t.Errorf("got notice %+v, want %+v", parsed.Extension, want)
+	}
+
+	if !parsed.Diagnostics.Empty() {
+		t.Errorf("unexpected diagnostics %+v", parsed.Diagnostics)
+	}
+}
+
+func TestExtensionDaysFromDates(t *testing.T) {
+	body := withoutRow(t, fixture(t, efdtest.ExtensionFixtureID).Body, "Extension Length")
+
+	notice, err := efd.ParseExtension(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("ParseExtension: %v", err)
+	}
+
+	if notice.ExtensionLength != "" || notice.ExtensionDays != 90 {
+		t.Errorf("got length %q of %d days, want 90 days from the due dates", notice.ExtensionLength, notice.ExtensionDays)
+	}
+}
+
+func TestExtensionMissingDueDate(t *testing.T) {
+	report := fixture(t, efdtest.ExtensionFixtureID)
+	report.Body = withoutRow(t, report.Body, "New Due Date")
+
+	_, err := fetchOnly(t, report)
+
+	var parseErr *efd.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Section != "Extension Details" || parseErr.ReportID != report.ID ||
+		!strings.Contains(parseErr.Error(), "missing a due date") {
+		t.Errorf("got error %v, want a ParseError for the missing due date", err)
+	}
+}
+
+func TestExtensionInvalidDueDate(t *testing.T) {
+	body := strings.Replace(fixture(t, efdtest.ExtensionFixtureID).Body, "<td>05/15/2020</td>", "<td>soon</td>", 1)
+
+	_, err := efd.ParseExtension(strings.NewReader(body))
+
+	var parseErr *efd.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Row != 1 {
+		t.Errorf("got error %v, want a ParseError for row 1", err)
+	}
+}
@@ -0,0 +1,99 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultFetchWorkers is the number of workers used by FetchAll when none are specified
+const DefaultFetchWorkers int = 4
+
+// FetchOptions controls how FetchAll retrieves reports
+type FetchOptions struct {
+	// Workers is the number of reports fetched concurrently, zero uses DefaultFetchWorkers
+	// Requests are still subject to any RateLimit configured on the client
+	Workers int
+}
+
+// FetchResult is the outcome of fetching and parsing a single SearchResult
+type FetchResult struct {
+	Result SearchResult
+	Report ParsedReport
+	Err    error
+}
+
+// FetchAll fetches and parses each of the search results with HandleResultContext using a pool of workers
+// sharing the client session, and sends each outcome on the returned channel as it completes
+// Results are sent in completion order, and the channel is closed once every result has been handled
+// If the context is cancelled, results which have not been started are not sent and the channel is closed early
+// The caller must either drain the channel or cancel the context to release the workers
+func (c *EFDClient) FetchAll(ctx context.Context, results []SearchResult, opts FetchOptions) <-chan FetchResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultFetchWorkers
+	}
+
+	if workers > len(results) {
+		workers = len(results)
+	}
+
+	out := make(chan FetchResult)
+	jobs := make(chan SearchResult)
+
+	go func() {
+		defer close(out)
+
+		// Accept the disclaimer up front so the workers start with a shared session
+		// rather than all racing to establish one
+		if len(results) > 0 {
+			if _, err := c.ensureSession(ctx); err != nil {
+				for _, result := range results {
+					if !sendFetchResult(ctx, out, FetchResult{Result: result, Err: err}) {
+						return
+					}
+				}
+
+				return
+			}
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go func() {
+				defer wg.Done()
+				for result := range jobs {
+					report, err := c.HandleResultContext(ctx, result)
+					if !sendFetchResult(ctx, out, FetchResult{Result: result, Report: report, Err: err}) {
+						return
+					}
+				}
+			}()
+		}
+
+	dispatch:
+		for _, result := range results {
+			select {
+			case jobs <- result:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+
+		close(jobs)
+		wg.Wait()
+	}()
+
+	return out
+}
+
+// sendFetchResult sends a result on out unless the context is done first, returning whether it was sent
+func sendFetchResult(ctx context.Context, out chan<- FetchResult, result FetchResult) bool {
+	select {
+	case out <- result:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,163 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+)
+
+// DefaultPageSize is the number of search results requested per page when no page size is provided
+const DefaultPageSize int = 100
+
+// searchPageFunc fetches a page of search results starting at start, recording malformed records in diag
+// It returns the results and the number of records remaining after the page
+type searchPageFunc func(ctx context.Context, start int, length int, diag *ParseReport) ([]SearchResult, int, error)
+
+// searchPage is a page of search results fetched in the background
+type searchPage struct {
+	results   []SearchResult
+	remainder int
+	diag      ParseReport
+	err       error
+}
+
+// SearchIterator lazily steps through search results a page at a time
+// While the results of one page are being consumed the next page is fetched in the background,
+// so only two pages are held in memory at once
+// A SearchIterator is not safe for concurrent use
+type SearchIterator struct {
+	ctx      context.Context
+	fetch    searchPageFunc
+	pageSize int
+	total    int
+	next     int
+	pending  chan searchPage
+	page     []SearchResult
+	result   SearchResult
+	diag     ParseReport
+	err      error
+}
+
+// IterateSearch validates the query and returns a SearchIterator which fetches pages of results lazily
+// The first page is fetched before returning so that RecordsTotal is available immediately
+// Iteration stops early, with Err returning the context error, if the context is cancelled
+func (c *EFDClient) IterateSearch(ctx context.Context, q SearchQuery) (*SearchIterator, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	q = q.withDefaults()
+
+	return newSearchIterator(ctx, func(ctx context.Context, start int, length int, diag *ParseReport) ([]SearchResult, int, error) {
+		return c.searchReportDataPaged(ctx, q, start, length, diag)
+	}, q.PageSize)
+}
+
+// newSearchIterator creates a SearchIterator over the pages returned by fetch, fetching the first page immediately
+func newSearchIterator(ctx context.Context, fetch searchPageFunc, pageSize int) (*SearchIterator, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	it := &SearchIterator{
+		ctx:      ctx,
+		fetch:    fetch,
+		pageSize: pageSize,
+	}
+
+	var diag ParseReport
+	results, remainder, err := fetch(ctx, 0, pageSize, &diag)
+	if err != nil {
+		return nil, err
+	}
+
+	// remainder is (Total records - start - length) with a start of 0
+	it.total = remainder + pageSize
+	it.accept(results, remainder, diag)
+
+	return it, nil
+}
+
+// Next advances to the next search result, fetching the next page if needed
+// It returns false when there are no more results or an error occurred, which is available from Err
+func (it *SearchIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if it.pending == nil {
+			return false
+		}
+
+		var page searchPage
+		select {
+		case page = <-it.pending:
+		case <-it.ctx.Done():
+			it.err = it.ctx.Err()
+			return false
+		}
+
+		if page.err != nil {
+			it.err = page.err
+			return false
+		}
+
+		it.accept(page.results, page.remainder, page.diag)
+	}
+
+	it.result = it.page[0]
+	it.page = it.page[1:]
+
+	return true
+}
+
+// Result returns the search result Next advanced to
+func (it *SearchIterator) Result() SearchResult {
+	return it.result
+}
+
+// Err returns the error which stopped iteration, if any
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+// Diagnostics returns the malformed records skipped in the pages fetched so far
+// Once Next has returned false this covers every page
+func (it *SearchIterator) Diagnostics() ParseReport {
+	return it.diag
+}
+
+// RecordsTotal returns the total number of records matching the search as reported by efdsearch
+// Malformed records are skipped during iteration, so fewer results than this may be returned
+// The skipped records are listed by Diagnostics, or fail iteration if strict parsing is enabled
+func (it *SearchIterator) RecordsTotal() int {
+	return it.total
+}
+
+// accept stores a fetched page and starts fetching the following page if there is one
+func (it *SearchIterator) accept(results []SearchResult, remainder int, diag ParseReport) {
+	it.page = results
+	it.diag.SkippedRows = append(it.diag.SkippedRows, diag.SkippedRows...)
+	it.next += it.pageSize
+	it.pending = nil
+
+	// As long as (Total records - start - length) > 0, we have more records to read
+	if remainder <= 0 {
+		return
+	}
+
+	pending := make(chan searchPage, 1)
+	start := it.next
+	go func() {
+		var diag ParseReport
+		results, remainder, err := it.fetch(it.ctx, start, it.pageSize, &diag)
+		pending <- searchPage{results: results, remainder: remainder, diag: diag, err: err}
+	}()
+
+	it.pending = pending
+}
@@ -0,0 +1,117 @@
+package efd_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+func TestIterateSearchAllPages(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	it, err := server.EFDClient().IterateSearch(context.Background(), efd.SearchQuery{PageSize: 2})
+	if err != nil {
+		t.Fatalf("IterateSearch: %v", err)
+	}
+
+	if got := it.RecordsTotal(); got != len(efdtest.Fixtures()) {
+		t.Errorf("got RecordsTotal %d before the first Next, want %d", got, len(efdtest.Fixtures()))
+	}
+
+	seen := make(map[string]bool)
+	for it.Next() {
+		seen[it.Result().ReportID] = true
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	if len(seen) != len(efdtest.Fixtures()) {
+		t.Errorf("got %d distinct results, want %d", len(seen), len(efdtest.Fixtures()))
+	}
+
+	if got := server.Requests(searchDataPath); got != 3 {
+		t.Errorf("got %d search pages, want 3", got)
+	}
+}
+
+func TestIterateSearchStopsEarly(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	it, err := server.EFDClient().IterateSearch(context.Background(), efd.SearchQuery{PageSize: 1})
+	if err != nil {
+		t.Fatalf("IterateSearch: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if !it.Next() {
+			t.Fatalf("Next returned false after %d results: %v", i, it.Err())
+		}
+	}
+
+	// Give the background fetch of the following page time to finish
+	time.Sleep(50 * time.Millisecond)
+
+	if got := server.Requests(searchDataPath); got > 3 {
+		t.Errorf("got %d search pages after reading 2 results, want at most 3", got)
+	}
+}
+
+// mismatchedDateClient returns a client for server which expects a different submission date layout, so that every
+// search record is malformed
+func mismatchedDateClient(server *efdtest.Server, opts ...efd.ClientOption) *efd.EFDClient {
+	baseURL, _ := url.Parse(server.URL)
+	opts = append([]efd.ClientOption{efd.WithBaseURL(baseURL), efd.WithHTTPClient(server.Client())}, opts...)
+
+	return efd.CreateEFDClient("", "2006-01-02", opts...)
+}
+
+func TestIterateSearchDiagnostics(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	it, err := mismatchedDateClient(server).IterateSearch(context.Background(), efd.SearchQuery{PageSize: 2})
+	if err != nil {
+		t.Fatalf("IterateSearch: %v", err)
+	}
+
+	for it.Next() {
+		t.Errorf("got result %s from a malformed record", it.Result().ReportID)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	skipped := it.Diagnostics().SkippedRows
+	if len(skipped) != len(efdtest.Fixtures()) {
+		t.Fatalf("got %d skipped records, want %d", len(skipped), len(efdtest.Fixtures()))
+	}
+
+	for i, row := range skipped {
+		if row.Index != i || row.Reason != "could not parse date submitted" || row.Raw == "" {
+			t.Errorf("unexpected skipped record %+v", row)
+		}
+	}
+}
+
+func TestIterateSearchStrict(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	client := mismatchedDateClient(server, efd.WithStrictParsing())
+	_, err := client.IterateSearch(context.Background(), efd.SearchQuery{PageSize: 2})
+
+	var parseErr *efd.ParseError
+	if !errors.Is(err, efd.ErrRowSkipped) || !errors.As(err, &parseErr) {
+		t.Errorf("got error %v, want a ParseError wrapping ErrRowSkipped", err)
+	}
+}
@@ -0,0 +1,201 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// TransactionKind enumerates the transaction types listed on PTRs and Annual reports
+type TransactionKind int
+
+// Enumeration of transaction types
+// UnknownTransaction is used for any type not listed here, with the raw text kept in Transaction.Type
+const (
+	UnknownTransaction TransactionKind = iota
+	PurchaseTransaction
+	SaleTransaction
+	PartialSaleTransaction
+	FullSaleTransaction
+	ExchangeTransaction
+)
+
+// OwnerKind enumerates the owners an asset or transaction can be reported for
+type OwnerKind int
+
+// Enumeration of owners
+// UnknownOwner is used for any owner not listed here, with the raw text kept in Transaction.Owner
+const (
+	UnknownOwner OwnerKind = iota
+	SelfOwner
+	SpouseOwner
+	JointOwner
+	DependentChildOwner
+)
+
+// AssetKind enumerates the asset types listed on PTRs
+type AssetKind int
+
+// Enumeration of asset types
+// UnknownAsset is used for any type not listed here, with the raw text kept in Transaction.AssetType
+const (
+	UnknownAsset AssetKind = iota
+	StockAsset
+	StockOptionAsset
+	CorporateBondAsset
+	MunicipalSecurityAsset
+	GovernmentSecurityAsset
+	MutualFundAsset
+	NonPublicStockAsset
+	OtherSecuritiesAsset
+	CryptocurrencyAsset
+	CommoditiesAsset
+	OtherAsset
+)
+
+// transactionKindCodes are the stable string codes used when marshalling each TransactionKind
+var transactionKindCodes = []string{"unknown", "purchase", "sale", "sale_partial", "sale_full", "exchange"}
+
+// transactionKindLabels maps normalized labels from efdsearch to a TransactionKind
+var transactionKindLabels = map[string]int{
+	"purchase":       int(PurchaseTransaction),
+	"sale":           int(SaleTransaction),
+	"sale (partial)": int(PartialSaleTransaction),
+	"sale (full)":    int(FullSaleTransaction),
+	"exchange":       int(ExchangeTransaction),
+}
+
+// ownerKindCodes are the stable string codes used when marshalling each OwnerKind
+var ownerKindCodes = []string{"unknown", "self", "spouse", "joint", "dependent_child"}
+
+// ownerKindLabels maps normalized labels from efdsearch to an OwnerKind
+var ownerKindLabels = map[string]int{
+	"self":            int(SelfOwner),
+	"spouse":          int(SpouseOwner),
+	"joint":           int(JointOwner),
+	"dependent child": int(DependentChildOwner),
+	"child":           int(DependentChildOwner),
+}
+
+// assetKindCodes are the stable string codes used when marshalling each AssetKind
+var assetKindCodes = []string{"unknown", "stock", "stock_option", "corporate_bond", "municipal_security",
+	"government_security", "mutual_fund", "non_public_stock", "other_securities", "cryptocurrency", "commodities", "other"}
+
+// assetKindLabels maps normalized labels from efdsearch to an AssetKind
+var assetKindLabels = map[string]int{
+	"stock":                 int(StockAsset),
+	"stocks":                int(StockAsset),
+	"stock option":          int(StockOptionAsset),
+	"stock options":         int(StockOptionAsset),
+	"corporate bond":        int(CorporateBondAsset),
+	"corporate bonds":       int(CorporateBondAsset),
+	"municipal security":    int(MunicipalSecurityAsset),
+	"municipal securities":  int(MunicipalSecurityAsset),
+	"government security":   int(GovernmentSecurityAsset),
+	"government securities": int(GovernmentSecurityAsset),
+	"mutual fund":           int(MutualFundAsset),
+	"mutual funds":          int(MutualFundAsset),
+	"non-public stock":      int(NonPublicStockAsset),
+	"other securities":      int(OtherSecuritiesAsset),
+	"cryptocurrency":        int(CryptocurrencyAsset),
+	"commodities/futures":   int(CommoditiesAsset),
+	"other":                 int(OtherAsset),
+}
+
+// ParseTransactionKind returns the TransactionKind for a transaction type such as "Sale (Partial)"
+// Unrecognised types return UnknownTransaction
+func ParseTransactionKind(text string) TransactionKind {
+	return TransactionKind(parseKind(text, transactionKindLabels))
+}
+
+// ParseOwnerKind returns the OwnerKind for an owner such as "Spouse"
+// Unrecognised owners return UnknownOwner
+func ParseOwnerKind(text string) OwnerKind {
+	return OwnerKind(parseKind(text, ownerKindLabels))
+}
+
+// ParseAssetKind returns the AssetKind for an asset type such as "Stock Option"
+// Unrecognised types return UnknownAsset
+func ParseAssetKind(text string) AssetKind {
+	return AssetKind(parseKind(text, assetKindLabels))
+}
+
+// String returns the stable code of the TransactionKind
+func (k TransactionKind) String() string {
+	return kindCode(transactionKindCodes, int(k))
+}
+
+// MarshalText implements text marshalling to the stable code of the TransactionKind
+func (k TransactionKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements text unmarshalling from the stable code of a TransactionKind
+func (k *TransactionKind) UnmarshalText(b []byte) error {
+	i, err := kindFromCode(transactionKindCodes, string(b))
+	*k = TransactionKind(i)
+	return err
+}
+
+// String returns the stable code of the OwnerKind
+func (k OwnerKind) String() string {
+	return kindCode(ownerKindCodes, int(k))
+}
+
+// MarshalText implements text marshalling to the stable code of the OwnerKind
+func (k OwnerKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements text unmarshalling from the stable code of an OwnerKind
+func (k *OwnerKind) UnmarshalText(b []byte) error {
+	i, err := kindFromCode(ownerKindCodes, string(b))
+	*k = OwnerKind(i)
+	return err
+}
+
+// String returns the stable code of the AssetKind
+func (k AssetKind) String() string {
+	return kindCode(assetKindCodes, int(k))
+}
+
+// MarshalText implements text marshalling to the stable code of the AssetKind
+func (k AssetKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements text unmarshalling from the stable code of an AssetKind
+func (k *AssetKind) UnmarshalText(b []byte) error {
+	i, err := kindFromCode(assetKindCodes, string(b))
+	*k = AssetKind(i)
+	return err
+}
+
+// parseKind looks up the label with entities decoded, case folded, and whitespace collapsed
+// Unrecognised labels return 0, which is the Unknown value of every kind
+func parseKind(text string, labels map[string]int) int {
+	label := strings.ToLower(strings.Join(strings.Fields(html.UnescapeString(text)), " "))
+
+	return labels[label]
+}
+
+// kindCode returns the code for a kind, falling back to the unknown code for values out of range
+func kindCode(codes []string, i int) string {
+	if i < 0 || i >= len(codes) {
+		return codes[0]
+	}
+
+	return codes[i]
+}
+
+// kindFromCode returns the kind for a code, or an error and the unknown kind if the code is not recognised
+func kindFromCode(codes []string, code string) (int, error) {
+	for i, c := range codes {
+		if c == code {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("efd unknown kind code %q", code)
+}
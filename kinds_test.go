@@ -0,0 +1,133 @@
+package efd_test
+
+import (
+	"encoding"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// kind is implemented by pointers to each of the kind enums
+type kind interface {
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+}
+
+func TestKindCodes(t *testing.T) {
+	exchange, partial := efd.ExchangeTransaction, efd.PartialSaleTransaction
+	child, option, commodities := efd.DependentChildOwner, efd.StockOptionAsset, efd.CommoditiesAsset
+
+	for _, test := range []struct {
+		value  kind
+		decode kind
+		code   string
+	}{
+		{new(efd.TransactionKind), new(efd.TransactionKind), "unknown"},
+		{&partial, new(efd.TransactionKind), "sale_partial"},
+		{&exchange, new(efd.TransactionKind), "exchange"},
+		{&child, new(efd.OwnerKind), "dependent_child"},
+		{&option, new(efd.AssetKind), "stock_option"},
+		{&commodities, new(efd.AssetKind), "commodities"},
+	} {
+		code, err := test.value.MarshalText()
+		if err != nil || string(code) != test.code {
+			t.Errorf("%v marshalled to %q, %v, want %q", test.value, code, err, test.code)
+			continue
+		}
+
+		if err := test.decode.UnmarshalText(code); err != nil {
+			t.Errorf("UnmarshalText(%q): %v", code, err)
+		}
+
+		if again, _ := test.decode.MarshalText(); string(again) != test.code {
+			t.Errorf("%q round tripped to %q", test.code, again)
+		}
+	}
+}
+
+func TestKindUnknownCode(t *testing.T) {
+	k := efd.SaleTransaction
+	if err := k.UnmarshalText([]byte("gift")); err == nil || !strings.Contains(err.Error(), `"gift"`) {
+		t.Errorf("got error %v for an unknown code", err)
+	}
+
+	if k != efd.UnknownTransaction {
+		t.Errorf("got %v for an unknown code, want UnknownTransaction", k)
+	}
+
+	var transaction efd.Transaction
+	if err := json.Unmarshal([]byte(`{"ownerkind":"cousin"}`), &transaction); err == nil {
+		t.Error("an unknown owner code was accepted")
+	}
+}
+
+func TestParseKindLabels(t *testing.T) {
+	if got := efd.ParseTransactionKind(" Sale  (Partial) "); got != efd.PartialSaleTransaction {
+		t.Errorf("got %v for Sale (Partial)", got)
+	}
+
+	if got := efd.ParseTransactionKind("Gift"); got != efd.UnknownTransaction {
+		t.Errorf("got %v for Gift, want unknown", got)
+	}
+
+	if got := efd.ParseOwnerKind("Child"); got != efd.DependentChildOwner {
+		t.Errorf("got %v for Child", got)
+	}
+
+	if got := efd.ParseAssetKind("Commodities/Futures"); got != efd.CommoditiesAsset {
+		t.Errorf("got %v for Commodities/Futures", got)
+	}
+}
+
+func TestTransactionKindsJSON(t *testing.T) {
+	parsed, err := fetchOnly(t, fixture(t, efdtest.PTRFixtureID))
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	out, err := json.Marshal(parsed.Transactions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded []struct {
+		Ticker    string `json:"ticker"`
+		OwnerKind string `json:"ownerkind"`
+		AssetKind string `json:"assetkind"`
+		Kind      string `json:"kind"`
+	}
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, transaction := range decoded {
+		if transaction.Ticker != "MSFT" {
+			continue
+		}
+
+		found = true
+		if transaction.OwnerKind != "self" || transaction.AssetKind != "stock_option" || transaction.Kind != "purchase" {
+			t.Errorf("unexpected codes for MSFT: %+v", transaction)
+		}
+	}
+
+	if !found {
+		t.Error("MSFT transaction missing")
+	}
+
+	var roundTrip []efd.Transaction
+	if err := json.Unmarshal(out, &roundTrip); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	for i := range roundTrip {
+		if roundTrip[i].Kind != parsed.Transactions[i].Kind || roundTrip[i].OwnerKind != parsed.Transactions[i].OwnerKind ||
+			roundTrip[i].AssetKind != parsed.Transactions[i].AssetKind {
+			t.Errorf("transaction %d kinds changed in a json round trip", i)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OptionType indicates whether an option is a call or a put
+type OptionType int
+
+// Enumeration of option types
+const (
+	UnknownOption OptionType = iota
+	CallOption
+	PutOption
+)
+
+// optionTypeCodes are the stable string codes used when marshalling each OptionType
+var optionTypeCodes = []string{"unknown", "call", "put"}
+
+// optionTypeLabels maps normalized labels from efdsearch to an OptionType
+var optionTypeLabels = map[string]int{
+	"call":  int(CallOption),
+	"calls": int(CallOption),
+	"put":   int(PutOption),
+	"puts":  int(PutOption),
+}
+
+// OptionDetails is a struct containing the terms of an option, parsed from the asset name and comment of a transaction
+// Strike is in cents, and fields which could not be found are left as their zero value
+type OptionDetails struct {
+	Type       OptionType `json:"type"`
+	Strike     int64      `json:"strike,omitempty"`
+	Expiration time.Time  `json:"expiration"`
+	Contracts  int        `json:"contracts,omitempty"`
+	Shares     int        `json:"shares,omitempty"`
+	Underlying string     `json:"underlying,omitempty"`
+}
+
+// Patterns for the option terms efdsearch lists under the asset name, and quantities commonly given in comments
+var (
+	optionTypePattern       = regexp.MustCompile(`(?i)option type:?\s*(calls?|puts?)\b`)
+	optionLoosePattern      = regexp.MustCompile(`(?i)\b(calls?|puts?)\b`)
+	optionStrikePattern     = regexp.MustCompile(`(?i)strike(?:\s+price)?:?\s*\$\s*(\d[\d,]*)(?:\.(\d{1,2}))?`)
+	optionExpiresPattern    = regexp.MustCompile(`(?i)expir(?:ation date|ation|es|y)?:?\s*(\d{1,2}/\d{1,2}/\d{4})`)
+	optionContractsPattern  = regexp.MustCompile(`(?i)(\d[\d,]*)\s+contracts?\b`)
+	optionSharesPattern     = regexp.MustCompile(`(?i)(\d[\d,]*)\s+shares?\b`)
+	optionUnderlyingPattern = regexp.MustCompile(`(?i:underlying(?:\s+(?:asset|ticker|security))?):?\s*([A-Z][A-Z.]*)\b`)
+)
+
+// ParseOptionType returns the OptionType for a label such as "Call"
+// Unrecognised labels return UnknownOption
+func ParseOptionType(text string) OptionType {
+	return OptionType(parseKind(text, optionTypeLabels))
+}
+
+// String returns the stable code of the OptionType
+func (t OptionType) String() string {
+	return kindCode(optionTypeCodes, int(t))
+}
+
+// MarshalText implements text marshalling to the stable code of the OptionType
+func (t OptionType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements text unmarshalling from the stable code of an OptionType
+func (t *OptionType) UnmarshalText(b []byte) error {
+	i, err := kindFromCode(optionTypeCodes, string(b))
+	*t = OptionType(i)
+	return err
+}
+
+// isOption reports whether a transaction is for an option
+// Tables without an asset type column fall back to looking for the option terms in the asset name
+func isOption(transaction *Transaction) bool {
+	if transaction.AssetKind == StockOptionAsset {
+		return true
+	}
+
+	return transaction.AssetType == "" && optionTypePattern.MatchString(transaction.AssetName)
+}
+
+// parseOptionDetails parses the terms of an option out of the free text of a transaction
+// The underlying is taken from the ticker column unless the text names one
+func (c *EFDClient) parseOptionDetails(transaction *Transaction) *OptionDetails {
+	var details OptionDetails
+
+	text := transaction.AssetName + " " + transaction.Comment
+
+	if match := optionTypePattern.FindStringSubmatch(text); match != nil {
+		details.Type = ParseOptionType(match[1])
+	} else if match := optionLoosePattern.FindStringSubmatch(text); match != nil {
+		details.Type = ParseOptionType(match[1])
+	}
+
+	if match := optionStrikePattern.FindStringSubmatch(text); match != nil {
+		details.Strike, _ = parseCents(match[1], match[2])
+	}
+
+	if match := optionExpiresPattern.FindStringSubmatch(text); match != nil {
+		details.Expiration, _ = time.Parse(c.dateLayout, match[1])
+	}
+
+	if match := optionContractsPattern.FindStringSubmatch(text); match != nil {
+		details.Contracts, _ = strconv.Atoi(strings.Replace(match[1], ",", "", -1))
+	}
+
+	if match := optionSharesPattern.FindStringSubmatch(text); match != nil {
+		details.Shares, _ = strconv.Atoi(strings.Replace(match[1], ",", "", -1))
+	}
+
+	if match := optionUnderlyingPattern.FindStringSubmatch(text); match != nil {
+		details.Underlying = match[1]
+	} else if transaction.Ticker != "--" {
+		details.Underlying = transaction.Ticker
+	}
+
+	return &details
+}
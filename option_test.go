@@ -0,0 +1,68 @@
+package efd_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+func TestOptionDetailsFromServer(t *testing.T) {
+	parsed, err := fetchOnly(t, fixture(t, efdtest.PTRFixtureID))
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	want := efd.OptionDetails{
+		Type:       efd.CallOption,
+		Strike:     15000,
+		Expiration: time.Date(2020, time.June, 19, 0, 0, 0, 0, time.UTC),
+		Contracts:  10,
+		Underlying: "MSFT",
+	}
+
+	found := false
+	for _, transaction := range parsed.Transactions {
+		if transaction.Ticker != "MSFT" {
+			if transaction.Option != nil {
+				t.Errorf("%s has option details %+v", transaction.AssetName, transaction.Option)
+			}
+
+			continue
+		}
+
+		found = true
+		if transaction.Option == nil || *transaction.Option != want {
+			t.Errorf("got option %+v, want %+v", transaction.Option, want)
+		}
+
+		if transaction.AssetName == "" {
+			t.Error("the raw asset name was not kept")
+		}
+	}
+
+	if !found {
+		t.Fatal("MSFT transaction missing")
+	}
+}
+
+func TestOptionTypeCodes(t *testing.T) {
+	for label, want := range map[string]efd.OptionType{"Call": efd.CallOption, "puts": efd.PutOption, "Swap": efd.UnknownOption} {
+		if got := efd.ParseOptionType(label); got != want {
+			t.Errorf("got %v for %q, want %v", got, label, want)
+		}
+	}
+
+	put := efd.PutOption
+	code, _ := put.MarshalText()
+
+	var decoded efd.OptionType
+	if err := decoded.UnmarshalText(code); err != nil || string(code) != "put" || decoded != efd.PutOption {
+		t.Errorf("put round tripped through %q to %v, %v", code, decoded, err)
+	}
+
+	if err := decoded.UnmarshalText([]byte("straddle")); err == nil || decoded != efd.UnknownOption {
+		t.Errorf("got %v, %v for an unknown code", decoded, err)
+	}
+}
@@ -0,0 +1,131 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ClientOption configures optional parameters of an EFDClient when passed to CreateEFDClient
+type ClientOption func(*clientOptions)
+
+// clientOptions collects the values set by ClientOptions
+// It is retained on the client so the underlying HTTP clients can be rebuilt by clearClient
+type clientOptions struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    time.Duration
+	proxy      func(*http.Request) (*url.URL, error)
+	tlsConfig  *tls.Config
+	retry      RetryPolicy
+	rateLimit  RateLimit
+	archive    Sink
+	strict     bool
+}
+
+// WithBaseURL points the client at a different efdsearch host, such as a local mirror or test server
+// Only the scheme and host of the URL are used, as efdsearch links to reports with absolute paths
+func WithBaseURL(baseURL *url.URL) ClientOption {
+	return func(o *clientOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient uses the provided http.Client as a template for the clients used by EFDClient
+// The client is copied and its Jar is replaced, as EFDClient manages its own session cookies
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithTransport sets the RoundTripper used for all requests, overriding any set on a WithHTTPClient client
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(o *clientOptions) {
+		o.transport = transport
+	}
+}
+
+// WithTimeout sets the timeout of each individual request
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithProxy sets the proxy function used for requests, e.g. http.ProxyURL(proxyURL)
+// This is only applied when the transport in use is an *http.Transport
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) ClientOption {
+	return func(o *clientOptions) {
+		o.proxy = proxy
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used for requests
+// This is only applied when the transport in use is an *http.Transport
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(o *clientOptions) {
+		o.tlsConfig = config
+	}
+}
+
+// resolveTransport determines the RoundTripper to use from the configured options
+// Proxy and TLS settings are applied to a clone so that shared transports such as
+// http.DefaultTransport are never modified
+func (o clientOptions) resolveTransport() http.RoundTripper {
+	var transport http.RoundTripper
+
+	if o.httpClient != nil {
+		transport = o.httpClient.Transport
+	}
+
+	if o.transport != nil {
+		transport = o.transport
+	}
+
+	if o.proxy == nil && o.tlsConfig == nil {
+		return transport
+	}
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	t, ok := transport.(*http.Transport)
+	if !ok {
+		return transport
+	}
+
+	t = t.Clone()
+	if o.proxy != nil {
+		t.Proxy = o.proxy
+	}
+
+	if o.tlsConfig != nil {
+		t.TLSClientConfig = o.tlsConfig
+	}
+
+	return t
+}
+
+// newHTTPClient builds an http.Client from the configured options using the provided transport and cookie jar
+// A nil jar is valid and produces a client which does not track cookies
+func (o clientOptions) newHTTPClient(transport http.RoundTripper, jar http.CookieJar) *http.Client {
+	var client http.Client
+
+	if o.httpClient != nil {
+		client = *o.httpClient
+	}
+
+	client.Transport = transport
+	client.Jar = jar
+
+	if o.timeout != 0 {
+		client.Timeout = o.timeout
+	}
+
+	return &client
+}
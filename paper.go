@@ -0,0 +1,107 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// PaperPage describes a page image saved by DownloadPaperPages
+// Existing is set if the page was already in the Sink and so was not downloaded again
+type PaperPage struct {
+	Page     int
+	URL      *url.URL
+	Name     string
+	Existing bool
+}
+
+// DownloadPaperPages fetches each page image of a paper report with the client session and stores it in sink
+// Pages are named <ReportID>/page_<n> with the extension of the image URL, e.g. <ReportID>/page_001.gif, so pages
+// which are already in the sink are skipped
+// Pages with no URL are left out, and the pages after them keep their number
+// Relative page URLs are resolved against the client base URL, and responses which are not images fail with an error
+// wrapping ErrUnexpectedContentType
+// The pages stored before any error are returned along with it
+func (c *EFDClient) DownloadPaperPages(ctx context.Context, report PaperReport, sink Sink) ([]PaperPage, error) {
+	if report.ReportID == "" {
+		return nil, errors.New("efd paper report has no ReportID")
+	}
+
+	pages := make([]PaperPage, 0, len(report.PageURLs))
+	for i, pageURL := range report.PageURLs {
+		if pageURL == nil {
+			continue
+		}
+
+		page := PaperPage{Page: i + 1, URL: c.resolvePageURL(pageURL)}
+		page.Name = paperPageName(report.ReportID, page.Page, page.URL)
+
+		exists, err := sink.Exists(ctx, page.Name)
+		if err != nil {
+			return pages, fmt.Errorf("checking page %d of %s: %w", page.Page, report.ReportID, err)
+		}
+
+		if exists {
+			page.Existing = true
+			pages = append(pages, page)
+			continue
+		}
+
+		body, err := c.fetchPaperPage(ctx, page.URL)
+		if err != nil {
+			return pages, fmt.Errorf("downloading page %d of %s: %w", page.Page, report.ReportID, err)
+		}
+
+		err = sink.Put(ctx, page.Name, body)
+		if err != nil {
+			return pages, fmt.Errorf("storing page %d of %s: %w", page.Page, report.ReportID, err)
+		}
+
+		pages = append(pages, page)
+	}
+
+	return pages, nil
+}
+
+// resolvePageURL resolves a possibly relative page image URL against the client base URL
+func (c *EFDClient) resolvePageURL(u *url.URL) *url.URL {
+	if u.IsAbs() {
+		return u
+	}
+
+	return c.baseURL.ResolveReference(u)
+}
+
+// fetchPaperPage downloads a page image with the client session, checking that an image was returned
+func (c *EFDClient) fetchPaperPage(ctx context.Context, u *url.URL) ([]byte, error) {
+	resp, err := c.getReport(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	contentType := resp.Header.Get("content-type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		return nil, fmt.Errorf("%w: expected an image from %s, got %q", ErrUnexpectedContentType, u, contentType)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// paperPageName returns the Sink name for a page image, using the extension of its URL
+func paperPageName(reportID string, page int, u *url.URL) string {
+	ext := strings.ToLower(path.Ext(u.Path))
+	if ext == "" {
+		ext = ".img"
+	}
+
+	return path.Join(reportID, fmt.Sprintf("page_%03d%s", page, ext))
+}
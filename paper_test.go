@@ -0,0 +1,112 @@
+package efd_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// memSink is an in-memory Sink
+type memSink struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func newMemSink() *memSink {
+	return &memSink{blobs: make(map[string][]byte)}
+}
+
+func (s *memSink) Exists(ctx context.Context, name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.blobs[name]
+	return ok, nil
+}
+
+func (s *memSink) Put(ctx context.Context, name string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blobs[name] = append([]byte(nil), body...)
+	return nil
+}
+
+func (s *memSink) Get(ctx context.Context, name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, ok := s.blobs[name]
+	if !ok {
+		return nil, fmt.Errorf("no blob named %s", name)
+	}
+
+	return body, nil
+}
+
+// paperPagePath is the path the paper fixture serves page n from
+func paperPagePath(n int) string {
+	return fmt.Sprintf("/media/public/%s/page_%d.gif", efdtest.PaperFixtureID, n)
+}
+
+func TestDownloadPaperPagesKeepsPageNumbers(t *testing.T) {
+	report := fixture(t, efdtest.PaperFixtureID)
+	report.Body = strings.Replace(report.Body, `src="`+paperPagePath(2)+`"`, `src=""`, 1)
+
+	server := efdtest.NewServer(report)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.EFDClient()
+
+	results, err := client.Search(ctx, efd.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	parsed, err := client.HandleResultContext(ctx, results[0])
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	if len(parsed.Pages.PageURLs) != 3 || parsed.Pages.PageURLs[1] != nil {
+		t.Fatalf("got page URLs %v, want a nil second page", parsed.Pages.PageURLs)
+	}
+
+	sink := newMemSink()
+	pages, err := client.DownloadPaperPages(ctx, parsed.Pages, sink)
+	if err != nil {
+		t.Fatalf("DownloadPaperPages: %v", err)
+	}
+
+	var names []string
+	for _, page := range pages {
+		names = append(names, page.Name)
+	}
+
+	want := []string{efdtest.PaperFixtureID + "/page_001.gif", efdtest.PaperFixtureID + "/page_003.gif"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got pages %v, want %v", names, want)
+	}
+
+	pages, err = client.DownloadPaperPages(ctx, parsed.Pages, sink)
+	if err != nil {
+		t.Fatalf("DownloadPaperPages: %v", err)
+	}
+
+	for _, page := range pages {
+		if !page.Existing {
+			t.Errorf("page %d was downloaded again", page.Page)
+		}
+	}
+
+	if got := server.Requests(paperPagePath(3)); got != 1 {
+		t.Errorf("page 3 was requested %d times, want 1", got)
+	}
+}
@@ -0,0 +1,126 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// offlineClient returns a client configured with the default date layout and base URL for parsing saved pages
+// It is never used to make requests
+func offlineClient() *EFDClient {
+	baseURL, _ := url.Parse(defaultBaseURL)
+
+	return &EFDClient{dateLayout: defaultDateLayout, baseURL: baseURL}
+}
+
+// readDocument parses a saved report page, wrapping any failure in a ParseError
+func readDocument(r io.Reader) (*goquery.Document, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, &ParseError{Section: "document", Row: -1, Err: err}
+	}
+
+	return doc, nil
+}
+
+// ParsePTR parses the transactions out of a saved digital PTR page
+// This makes no requests, so can be used to re-parse previously downloaded reports
+// Rows which cannot be parsed are skipped, ParseDocument can be used to list them
+func ParsePTR(r io.Reader) ([]Transaction, error) {
+	doc, err := readDocument(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var diag ParseReport
+	return offlineClient().parsePTRDocument(context.Background(), doc, &diag)
+}
+
+// ParseAnnual parses every part of a saved digital Annual report page
+func ParseAnnual(r io.Reader) (AnnualDisclosure, error) {
+	doc, err := readDocument(r)
+	if err != nil {
+		return AnnualDisclosure{}, err
+	}
+
+	var diag ParseReport
+	return offlineClient().parseAnnualDocument(context.Background(), doc, &diag)
+}
+
+// ParseExtension parses the details of a saved due date extension notice page
+func ParseExtension(r io.Reader) (ExtensionNotice, error) {
+	doc, err := readDocument(r)
+	if err != nil {
+		return ExtensionNotice{}, err
+	}
+
+	var diag ParseReport
+	return offlineClient().parseExtensionDocument(doc, &diag)
+}
+
+// ParsePaper collects the page image URLs of a saved paper report print page
+// Relative image URLs are resolved against baseURL, which is normally the URL the page was downloaded from
+// A nil baseURL leaves them unresolved and the ReportID empty
+func ParsePaper(r io.Reader, baseURL *url.URL) (PaperReport, error) {
+	doc, err := readDocument(r)
+	if err != nil {
+		return PaperReport{}, err
+	}
+
+	var diag ParseReport
+	return offlineClient().parsePaperDocument(doc, baseURL, &diag), nil
+}
+
+// ParseSearchData parses a saved page of json search results, as returned by the search data endpoint
+// Report links are resolved against https://efdsearch.senate.gov
+func ParseSearchData(r io.Reader) ([]SearchResult, error) {
+	var diag ParseReport
+
+	_, results, err := offlineClient().parseSearchData(r, 0, &diag)
+	return results, err
+}
+
+// ParseOptions configures ParseDocument
+// BaseURL is used to resolve the page URLs of paper reports, and Strict fails the parse if any row is skipped
+type ParseOptions struct {
+	BaseURL *url.URL
+	Strict  bool
+}
+
+// ParseDocument parses a saved report page of the given format, as HandleResult does for a live report
+// The ParsedReport includes Diagnostics listing any rows which were skipped
+func ParseDocument(r io.Reader, format ReportFormat, opts ParseOptions) (ParsedReport, error) {
+	var parsedReport ParsedReport
+
+	doc, err := readDocument(r)
+	if err != nil {
+		return parsedReport, err
+	}
+
+	c := offlineClient()
+	ctx := context.Background()
+	diag := &parsedReport.Diagnostics
+
+	parsedReport.ReportFormat = format
+	switch format {
+	case PTRFormat:
+		parsedReport.Transactions, err = c.parsePTRDocument(ctx, doc, diag)
+	case AnnualFormat:
+		parsedReport.Annual, err = c.parseAnnualDocument(ctx, doc, diag)
+		parsedReport.Transactions = parsedReport.Annual.allTransactions()
+	case PaperFormat:
+		parsedReport.Pages = c.parsePaperDocument(doc, opts.BaseURL, diag)
+	case DueDateExtensionFormat:
+		parsedReport.Extension, err = c.parseExtensionDocument(doc, diag)
+	}
+
+	if err == nil {
+		err = checkStrict(opts.Strict, diag)
+	}
+
+	return parsedReport, err
+}
@@ -0,0 +1,145 @@
+package efd_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+const efdsearchURL = "https://efdsearch.senate.gov"
+
+func TestParsePTRMatchesFetch(t *testing.T) {
+	report := fixture(t, efdtest.PTRFixtureID)
+	fetched, err := fetchOnly(t, report)
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	transactions, err := efd.ParsePTR(strings.NewReader(report.Body))
+	if err != nil {
+		t.Fatalf("ParsePTR: %v", err)
+	}
+
+	if len(transactions) != 4 || !reflect.DeepEqual(transactions, fetched.Transactions) {
+		t.Errorf("got transactions %+v, want %+v", transactions, fetched.Transactions)
+	}
+}
+
+func TestParseAnnualMatchesFetch(t *testing.T) {
+	report := fixture(t, efdtest.AnnualFixtureID)
+	fetched, err := fetchOnly(t, report)
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	annual, err := efd.ParseAnnual(strings.NewReader(report.Body))
+	if err != nil {
+		t.Fatalf("ParseAnnual: %v", err)
+	}
+
+	if len(annual.Assets) == 0 || !reflect.DeepEqual(annual, fetched.Annual) {
+		t.Errorf("got annual %+v, want %+v", annual, fetched.Annual)
+	}
+}
+
+func TestParseExtensionMatchesFetch(t *testing.T) {
+	report := fixture(t, efdtest.ExtensionFixtureID)
+	fetched, err := fetchOnly(t, report)
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	notice, err := efd.ParseExtension(strings.NewReader(report.Body))
+	if err != nil {
+		t.Fatalf("ParseExtension: %v", err)
+	}
+
+	if notice.ReportExtended == "" || !reflect.DeepEqual(notice, fetched.Extension) {
+		t.Errorf("got notice %+v, want %+v", notice, fetched.Extension)
+	}
+}
+
+func TestParsePaper(t *testing.T) {
+	report := fixture(t, efdtest.PaperFixtureID)
+	base, _ := url.Parse(efdsearchURL + report.Path())
+
+	for _, test := range []struct {
+		name   string
+		base   *url.URL
+		id     string
+		prefix string
+	}{
+		{"with base URL", base, efdtest.PaperFixtureID, efdsearchURL},
+		{"without base URL", nil, "", ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			paper, err := efd.ParsePaper(strings.NewReader(report.Body), test.base)
+			if err != nil {
+				t.Fatalf("ParsePaper: %v", err)
+			}
+
+			if paper.ReportID != test.id {
+				t.Errorf("got ReportID %q, want %q", paper.ReportID, test.id)
+			}
+
+			if len(paper.PageURLs) != efdtest.PaperFixturePages {
+				t.Fatalf("got %d pages, want %d", len(paper.PageURLs), efdtest.PaperFixturePages)
+			}
+
+			for i, pageURL := range paper.PageURLs {
+				if want := test.prefix + paperPagePath(i+1); pageURL == nil || pageURL.String() != want {
+					t.Errorf("got page %d URL %v, want %s", i+1, pageURL, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSearchDataResolvesLinks(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	sink := newMemSink()
+	if _, err := server.EFDClient(efd.WithArchive(sink)).Search(context.Background(), efd.SearchQuery{}); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	var page []byte
+	for name, body := range sink.blobs {
+		if strings.HasPrefix(name, "search/") && !strings.HasSuffix(name, ".meta.json") {
+			page = body
+		}
+	}
+
+	results, err := efd.ParseSearchData(bytes.NewReader(page))
+	if err != nil {
+		t.Fatalf("ParseSearchData: %v", err)
+	}
+
+	if len(results) != len(efdtest.Fixtures()) {
+		t.Fatalf("got %d results, want %d", len(results), len(efdtest.Fixtures()))
+	}
+
+	for _, result := range results {
+		report := fixture(t, result.ReportID)
+		want := efdsearchURL + report.Path()
+		if report.ReportFormat == efd.PaperFormat {
+			// Paper reports are read from their print view
+			want = strings.Replace(want, "/view/", "/print/", 1)
+		}
+
+		if result.ReportFormat != report.ReportFormat {
+			t.Errorf("got format %v for %s, want %v", result.ReportFormat, result.ReportID, report.ReportFormat)
+		}
+
+		if result.FileURL == nil || result.FileURL.String() != want {
+			t.Errorf("got URL %v for %s, want %s", result.FileURL, result.ReportID, want)
+		}
+	}
+}
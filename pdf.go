@@ -0,0 +1,388 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // Paper report pages are usually served as gifs
+	"image/jpeg"
+	_ "image/png" // Register png decoding for page images
+	"io"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// pdfPageWidth is the width in points of each PDF page, matching US Letter, with the height following the image
+const pdfPageWidth = 612.0
+
+// PDFOptions configures how paper report pages are written to a PDF
+// Pages larger than MaxWidth or MaxHeight pixels are downscaled to fit, with zero meaning no limit
+// A JPEGQuality between 1 and 100 re-encodes every page as a jpeg, which is usually much smaller than the default
+// lossless encoding
+type PDFOptions struct {
+	MaxWidth    int
+	MaxHeight   int
+	JPEGQuality int
+}
+
+// pdfImage is a page image encoded for embedding in a PDF
+type pdfImage struct {
+	width      int
+	height     int
+	colorSpace string
+	filter     string
+	data       []byte
+}
+
+// SavePaperPDF downloads every page of a paper report and stores them in sink as a single PDF named <ReportID>.pdf
+// The name of the stored PDF is returned
+func (c *EFDClient) SavePaperPDF(ctx context.Context, result SearchResult, report PaperReport, sink Sink,
+	opts PDFOptions) (string, error) {
+	var buf bytes.Buffer
+
+	if report.ReportID == "" {
+		return "", errors.New("efd paper report has no ReportID")
+	}
+
+	err := c.WritePaperPDF(ctx, result, report, &buf, opts)
+	if err != nil {
+		return "", err
+	}
+
+	name := report.ReportID + ".pdf"
+
+	return name, sink.Put(ctx, name, buf.Bytes())
+}
+
+// WritePaperPDF downloads every page of a paper report with the client session and writes them to w as a PDF,
+// one image per page in order
+// Pages with no URL are left out
+// The filer name, report name, submission date, and source URL from result are recorded in the document info
+func (c *EFDClient) WritePaperPDF(ctx context.Context, result SearchResult, report PaperReport, w io.Writer,
+	opts PDFOptions) error {
+	var images []pdfImage
+
+	for i, pageURL := range report.PageURLs {
+		if pageURL == nil {
+			continue
+		}
+
+		body, err := c.fetchPaperPage(ctx, c.resolvePageURL(pageURL))
+		if err != nil {
+			return fmt.Errorf("downloading page %d of %s: %w", i+1, report.ReportID, err)
+		}
+
+		img, err := encodePDFImage(body, opts)
+		if err != nil {
+			return &ParseError{ReportID: report.ReportID, Section: paperSection, Row: i, Err: err}
+		}
+
+		images = append(images, img)
+	}
+
+	if len(images) == 0 {
+		return fmt.Errorf("efd paper report %s has no pages", report.ReportID)
+	}
+
+	return writePDF(w, pdfInfo(result), images)
+}
+
+// pdfInfo builds the document info entries for a paper report
+func pdfInfo(result SearchResult) [][2]string {
+	info := [][2]string{
+		{"Title", result.ReportName},
+		{"Author", strings.Title(strings.TrimSpace(result.FirstName + " " + result.LastName))},
+		{"Producer", "go-efd"},
+		{"CreationDate", pdfDate(time.Now())},
+		{"ReportID", result.ReportID},
+	}
+
+	if !result.DateSubmitted.IsZero() {
+		info = append(info, [2]string{"DateSubmitted", pdfDate(result.DateSubmitted)})
+	}
+
+	if result.FileURL != nil {
+		info = append(info, [2]string{"Subject", "Source: " + result.FileURL.String()})
+		info = append(info, [2]string{"SourceURL", result.FileURL.String()})
+	}
+
+	return info
+}
+
+// encodePDFImage decodes a page image, downscales it if needed, and encodes it for embedding
+// Jpegs which need no changes are embedded as is
+func encodePDFImage(body []byte, opts PDFOptions) (pdfImage, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return pdfImage{}, err
+	}
+
+	width, height := fitSize(config.Width, config.Height, opts.MaxWidth, opts.MaxHeight)
+	if format == "jpeg" && width == config.Width && height == config.Height &&
+		(config.ColorModel == color.YCbCrModel || config.ColorModel == color.GrayModel) {
+		colorSpace := "DeviceRGB"
+		if config.ColorModel == color.GrayModel {
+			colorSpace = "DeviceGray"
+		}
+
+		return pdfImage{width: width, height: height, colorSpace: colorSpace, filter: "DCTDecode", data: body}, nil
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return pdfImage{}, err
+	}
+
+	gray := isGray(src)
+
+	// Flatten onto white so that transparent areas of gifs and pngs are not rendered black
+	flat := image.NewRGBA(image.Rect(0, 0, config.Width, config.Height))
+	draw.Draw(flat, flat.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, src.Bounds().Min, draw.Over)
+
+	if width != config.Width || height != config.Height {
+		flat = downscale(flat, width, height)
+	}
+
+	var img image.Image = flat
+	colorSpace := "DeviceRGB"
+	if gray {
+		grayImg := image.NewGray(flat.Bounds())
+		draw.Draw(grayImg, grayImg.Bounds(), flat, image.Point{}, draw.Src)
+		img = grayImg
+		colorSpace = "DeviceGray"
+	}
+
+	var buf bytes.Buffer
+	if opts.JPEGQuality > 0 {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: opts.JPEGQuality})
+		if err != nil {
+			return pdfImage{}, err
+		}
+
+		return pdfImage{width: width, height: height, colorSpace: colorSpace, filter: "DCTDecode", data: buf.Bytes()}, nil
+	}
+
+	zw := zlib.NewWriter(&buf)
+	switch img := img.(type) {
+	case *image.Gray:
+		zw.Write(img.Pix)
+	case *image.RGBA:
+		row := make([]byte, 3*width)
+		for y := 0; y < height; y++ {
+			pix := img.Pix[y*img.Stride:]
+			for x := 0; x < width; x++ {
+				copy(row[3*x:3*x+3], pix[4*x:4*x+3])
+			}
+			zw.Write(row)
+		}
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return pdfImage{}, err
+	}
+
+	return pdfImage{width: width, height: height, colorSpace: colorSpace, filter: "FlateDecode", data: buf.Bytes()}, nil
+}
+
+// fitSize scales width and height down to fit within maxWidth and maxHeight, keeping the aspect ratio
+// A limit of zero or less is ignored, and images are never scaled up
+func fitSize(width, height, maxWidth, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+
+	if maxHeight > 0 && height > maxHeight && float64(maxHeight)/float64(height) < scale {
+		scale = float64(maxHeight) / float64(height)
+	}
+
+	if scale == 1.0 {
+		return width, height
+	}
+
+	w, h := int(float64(width)*scale+0.5), int(float64(height)*scale+0.5)
+	if w < 1 {
+		w = 1
+	}
+
+	if h < 1 {
+		h = 1
+	}
+
+	return w, h
+}
+
+// downscale shrinks an image to width by height by averaging the source pixels covered by each destination pixel
+func downscale(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcH/height, (y+1)*srcH/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcW/width, (x+1)*srcW/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, n int
+			for sy := y0; sy < y1; sy++ {
+				pix := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					r += int(pix[4*sx])
+					g += int(pix[4*sx+1])
+					b += int(pix[4*sx+2])
+					n++
+				}
+			}
+
+			i := y*dst.Stride + 4*x
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+
+	return dst
+}
+
+// isGray reports whether an image only uses shades of gray, so it can be embedded with a single channel
+func isGray(img image.Image) bool {
+	switch img := img.(type) {
+	case *image.Gray, *image.Gray16:
+		return true
+	case *image.Paletted:
+		for _, c := range img.Palette {
+			r, g, b, _ := c.RGBA()
+			if r != g || g != b {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// writePDF writes a PDF with one page per image, each page scaled to pdfPageWidth points wide
+func writePDF(w io.Writer, info [][2]string, images []pdfImage) error {
+	var buf bytes.Buffer
+	var offsets []int
+
+	// Objects are numbered from 1, the catalog, page tree, and info come first followed by
+	// a page, contents stream, and image for each page
+	object := func(n int) {
+		for len(offsets) < n {
+			offsets = append(offsets, 0)
+		}
+
+		offsets[n-1] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", n)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	object(1)
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+
+	var kids []string
+	for i := range images {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 4+3*i))
+	}
+
+	object(2)
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(images))
+
+	object(3)
+	buf.WriteString("<<")
+	for _, entry := range info {
+		fmt.Fprintf(&buf, " /%s %s", entry[0], pdfString(entry[1]))
+	}
+	buf.WriteString(" >>\nendobj\n")
+
+	for i, img := range images {
+		page, contents, xobject := 4+3*i, 5+3*i, 6+3*i
+		height := pdfPageWidth * float64(img.height) / float64(img.width)
+
+		object(page)
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /XObject << /Im%d %d 0 R >> >> /Contents %d 0 R >>\nendobj\n",
+			pdfPageWidth, height, i+1, xobject, contents)
+
+		stream := fmt.Sprintf("q %.2f 0 0 %.2f 0 0 cm /Im%d Do Q", pdfPageWidth, height, i+1)
+		object(contents)
+		fmt.Fprintf(&buf, "<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(stream), stream)
+
+		object(xobject)
+		fmt.Fprintf(&buf, "<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s "+
+			"/BitsPerComponent 8 /Filter /%s /Length %d >>\nstream\n",
+			img.width, img.height, img.colorSpace, img.filter, len(img.data))
+		buf.Write(img.data)
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// pdfString encodes text as a PDF string, using a literal string for ascii text and UTF-16 otherwise
+func pdfString(s string) string {
+	var b strings.Builder
+
+	ascii := true
+	for _, r := range s {
+		if r < 0x20 || r > 0x7e {
+			ascii = false
+			break
+		}
+	}
+
+	if ascii {
+		b.WriteString("(")
+		for _, r := range s {
+			if r == '(' || r == ')' || r == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteString(")")
+
+		return b.String()
+	}
+
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">")
+
+	return b.String()
+}
+
+// pdfDate formats a time as a PDF date string
+func pdfDate(t time.Time) string {
+	return t.UTC().Format("D:20060102150405Z")
+}
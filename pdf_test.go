@@ -0,0 +1,46 @@
+package efd_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+func TestSavePaperPDF(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.EFDClient()
+	paper := fetchFixtures(t, client)[efdtest.PaperFixtureID]
+
+	sink := newMemSink()
+	name, err := client.SavePaperPDF(ctx, paper.Result, paper.Report.Pages, sink, efd.PDFOptions{MaxWidth: 10})
+	if err != nil {
+		t.Fatalf("SavePaperPDF: %v", err)
+	}
+
+	if name != efdtest.PaperFixtureID+".pdf" {
+		t.Errorf("got name %s", name)
+	}
+
+	pdf, err := sink.Get(ctx, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Error("output is not a complete PDF")
+	}
+
+	if got := bytes.Count(pdf, []byte("/Subtype /Image /Width 10 ")); got != efdtest.PaperFixturePages {
+		t.Errorf("got %d downscaled page images, want %d", got, efdtest.PaperFixturePages)
+	}
+
+	if !bytes.Contains(pdf, []byte("/Title (Periodic Transaction Report)")) {
+		t.Error("report name missing from the document info")
+	}
+}
@@ -0,0 +1,103 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AllFilerTypes lists every FilerType which can be searched for, used when a SearchQuery does not specify any
+var AllFilerTypes = []FilerType{SenatorFiler, CandidateFiler, FormerSenatorFiler}
+
+// AllReportTypes lists every ReportType which can be searched for, used when a SearchQuery does not specify any
+var AllReportTypes = []ReportType{AnnualReport, DueDateExtensionReport, PeriodicTransactionReport, BlindTrustReport, OtherDocumentsReport}
+
+// stateCodes is the set of two letter codes accepted for SearchQuery.State
+var stateCodes = map[string]bool{
+	"AL": true, "AK": true, "AZ": true, "AR": true, "CA": true, "CO": true, "CT": true, "DE": true, "FL": true, "GA": true,
+	"HI": true, "ID": true, "IL": true, "IN": true, "IA": true, "KS": true, "KY": true, "LA": true, "ME": true, "MD": true,
+	"MA": true, "MI": true, "MN": true, "MS": true, "MO": true, "MT": true, "NE": true, "NV": true, "NH": true, "NJ": true,
+	"NM": true, "NY": true, "NC": true, "ND": true, "OH": true, "OK": true, "OR": true, "PA": true, "RI": true, "SC": true,
+	"SD": true, "TN": true, "TX": true, "UT": true, "VT": true, "VA": true, "WA": true, "WV": true, "WI": true, "WY": true,
+}
+
+// SearchQuery describes a search of efdsearch reports
+// Zero values are treated as "any": empty names and State match everyone, empty FilerTypes and ReportTypes
+// search all types, and zero StartTime or EndTime leave that end of the date range open
+type SearchQuery struct {
+	FirstName   string
+	LastName    string
+	FilerTypes  []FilerType
+	State       string
+	ReportTypes []ReportType
+
+	// Only Day, Month, and Year are used, the range includes both the start and end day
+	StartTime time.Time
+	EndTime   time.Time
+
+	// PageSize is the number of results requested per page, zero uses DefaultPageSize
+	PageSize int
+}
+
+// Validate checks that the query only contains known filer and report types, a valid state code,
+// and a start time no later than its end time
+// Returned errors wrap ErrInvalidQuery
+func (q SearchQuery) Validate() error {
+	for _, filerType := range q.FilerTypes {
+		if !containsIntEnum(AllFilerTypes, filerType) {
+			return fmt.Errorf("%w: unknown filer type %d", ErrInvalidQuery, filerType)
+		}
+	}
+
+	for _, reportType := range q.ReportTypes {
+		if !containsIntEnum(AllReportTypes, reportType) {
+			return fmt.Errorf("%w: unknown report type %d", ErrInvalidQuery, reportType)
+		}
+	}
+
+	if q.State != "" && !stateCodes[strings.ToUpper(q.State)] {
+		return fmt.Errorf("%w: unknown state %q", ErrInvalidQuery, q.State)
+	}
+
+	if !q.StartTime.IsZero() && !q.EndTime.IsZero() && q.EndTime.Before(q.StartTime) {
+		return fmt.Errorf("%w: start time %s is after end time %s", ErrInvalidQuery,
+			q.StartTime.Format("01/02/2006"), q.EndTime.Format("01/02/2006"))
+	}
+
+	if q.PageSize < 0 {
+		return fmt.Errorf("%w: negative page size %d", ErrInvalidQuery, q.PageSize)
+	}
+
+	return nil
+}
+
+// withDefaults returns a copy of the query with empty type filters and page size filled in
+func (q SearchQuery) withDefaults() SearchQuery {
+	if len(q.FilerTypes) == 0 {
+		q.FilerTypes = AllFilerTypes
+	}
+
+	if len(q.ReportTypes) == 0 {
+		q.ReportTypes = AllReportTypes
+	}
+
+	if q.PageSize == 0 {
+		q.PageSize = DefaultPageSize
+	}
+
+	q.State = strings.ToUpper(q.State)
+
+	return q
+}
+
+// containsIntEnum reports whether val is present in vals
+func containsIntEnum(vals []intEnum, val intEnum) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+
+	return false
+}
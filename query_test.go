@@ -0,0 +1,64 @@
+package efd_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+func TestSearchQueryValidate(t *testing.T) {
+	start := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, test := range []struct {
+		name  string
+		query efd.SearchQuery
+		valid bool
+	}{
+		{"empty", efd.SearchQuery{}, true},
+		{"full", efd.SearchQuery{FirstName: "Jane", FilerTypes: []efd.FilerType{efd.SenatorFiler}, State: "va",
+			ReportTypes: []efd.ReportType{efd.PeriodicTransactionReport}, StartTime: start, EndTime: end, PageSize: 10}, true},
+		{"same day", efd.SearchQuery{StartTime: start, EndTime: start}, true},
+		{"unknown filer type", efd.SearchQuery{FilerTypes: []efd.FilerType{efd.SenatorFiler, efd.FilerType(99)}}, false},
+		{"unknown report type", efd.SearchQuery{ReportTypes: []efd.ReportType{efd.ReportType(99)}}, false},
+		{"invalid state", efd.SearchQuery{State: "XX"}, false},
+		{"start after end", efd.SearchQuery{StartTime: end, EndTime: start}, false},
+		{"negative page size", efd.SearchQuery{PageSize: -1}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.query.Validate()
+			if test.valid && err != nil {
+				t.Errorf("Validate: %v", err)
+			} else if !test.valid && !errors.Is(err, efd.ErrInvalidQuery) {
+				t.Errorf("got error %v, want ErrInvalidQuery", err)
+			}
+		})
+	}
+}
+
+func TestInvalidQueryMakesNoRequests(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.EFDClient()
+	query := efd.SearchQuery{State: "XX"}
+
+	if _, err := client.Search(ctx, query); !errors.Is(err, efd.ErrInvalidQuery) {
+		t.Errorf("Search returned %v, want ErrInvalidQuery", err)
+	}
+
+	if _, err := client.IterateSearch(ctx, query); !errors.Is(err, efd.ErrInvalidQuery) {
+		t.Errorf("IterateSearch returned %v, want ErrInvalidQuery", err)
+	}
+
+	for _, path := range []string{homePath, "/search/", searchDataPath} {
+		if got := server.Requests(path); got != 0 {
+			t.Errorf("got %d requests to %s for an invalid query", got, path)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RateLimit controls how quickly and how concurrently requests are sent to efdsearch
+// The limit is shared by every request made by a client, including search pagination, report fetches, and retries
+type RateLimit struct {
+	// RequestsPerSecond is the sustained request rate, zero disables rate limiting
+	RequestsPerSecond float64
+
+	// Burst is the number of requests which can be sent back to back before the rate applies, minimum 1
+	Burst int
+
+	// MaxConcurrent is the maximum number of requests in flight at once, zero leaves concurrency unlimited
+	MaxConcurrent int
+
+	// MinDelay and MaxDelay add a random delay in [MinDelay, MaxDelay) before each request
+	MinDelay time.Duration
+	MaxDelay time.Duration
+}
+
+// WithRateLimit sets the RateLimit applied to all requests made by the client
+// By default requests are not rate limited
+func WithRateLimit(limit RateLimit) ClientOption {
+	return func(o *clientOptions) {
+		o.rateLimit = limit
+	}
+}
+
+// rateLimiter is a token bucket with an optional concurrency limit and randomized delay
+type rateLimiter struct {
+	limit RateLimit
+	sem   chan struct{}
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a rateLimiter for the provided limit
+// A nil rateLimiter is returned if the limit does not restrict anything
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.RequestsPerSecond <= 0 && limit.MaxConcurrent <= 0 && limit.MaxDelay <= 0 && limit.MinDelay <= 0 {
+		return nil
+	}
+
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	r := &rateLimiter{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+	}
+
+	if limit.MaxConcurrent > 0 {
+		r.sem = make(chan struct{}, limit.MaxConcurrent)
+	}
+
+	return r
+}
+
+// acquire blocks until a request may be sent, or the context is done
+// On success the returned function must be called once the request has completed
+func (r *rateLimiter) acquire(ctx context.Context) (func(), error) {
+	if r == nil {
+		return func() {}, ctx.Err()
+	}
+
+	release := func() {}
+	if r.sem != nil {
+		select {
+		case r.sem <- struct{}{}:
+			release = func() { <-r.sem }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	delay := r.reserve() + r.randomDelay()
+	if err := sleepContext(ctx, delay); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// reserve takes a token from the bucket and returns how long to wait until it is available
+func (r *rateLimiter) reserve() time.Duration {
+	if r.limit.RequestsPerSecond <= 0 {
+		return 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if !r.last.IsZero() {
+		r.tokens += now.Sub(r.last).Seconds() * r.limit.RequestsPerSecond
+		if r.tokens > float64(r.limit.Burst) {
+			r.tokens = float64(r.limit.Burst)
+		}
+	}
+
+	r.last = now
+	r.tokens--
+
+	if r.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-r.tokens / r.limit.RequestsPerSecond * float64(time.Second))
+}
+
+// randomDelay returns a delay in [MinDelay, MaxDelay)
+func (r *rateLimiter) randomDelay() time.Duration {
+	if r.limit.MaxDelay <= r.limit.MinDelay {
+		return r.limit.MinDelay
+	}
+
+	return r.limit.MinDelay + time.Duration(rand.Int63n(int64(r.limit.MaxDelay-r.limit.MinDelay)))
+}
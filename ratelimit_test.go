@@ -0,0 +1,106 @@
+package efd_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+// timedSearch runs a search of one result per page and returns how long it took
+func timedSearch(t *testing.T, client *efd.EFDClient) time.Duration {
+	t.Helper()
+
+	start := time.Now()
+	if _, err := client.Search(context.Background(), efd.SearchQuery{PageSize: 1}); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	return time.Since(start)
+}
+
+func TestRateLimitSpacesRequests(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	limit := efd.RateLimit{RequestsPerSecond: 20, Burst: 2}
+	elapsed := timedSearch(t, server.EFDClient(efd.WithRateLimit(limit)))
+
+	pages := server.Requests(searchDataPath)
+	if pages != len(efdtest.Fixtures()) {
+		t.Fatalf("got %d search pages, want %d", pages, len(efdtest.Fixtures()))
+	}
+
+	want := time.Duration(float64(pages-limit.Burst) / limit.RequestsPerSecond * float64(time.Second))
+	if elapsed < want-10*time.Millisecond {
+		t.Errorf("%d requests took %v, want at least %v", pages, elapsed, want)
+	}
+}
+
+func TestRateLimitAllowsBurst(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	limit := efd.RateLimit{RequestsPerSecond: 1, Burst: len(efdtest.Fixtures())}
+	if elapsed := timedSearch(t, server.EFDClient(efd.WithRateLimit(limit))); elapsed > 500*time.Millisecond {
+		t.Errorf("a burst of %d requests took %v", limit.Burst, elapsed)
+	}
+}
+
+func TestRateLimitMaxConcurrent(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	server.SetLatency(20 * time.Millisecond)
+	client := server.EFDClient(efd.WithRateLimit(efd.RateLimit{MaxConcurrent: 2}))
+
+	ctx := context.Background()
+	results, err := client.Search(ctx, efd.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	for f := range client.FetchAll(ctx, results, efd.FetchOptions{Workers: 4}) {
+		if f.Err != nil {
+			t.Errorf("fetching %s: %v", f.Result.ReportID, f.Err)
+		}
+	}
+
+	if got := server.MaxInFlight(); got > 2 {
+		t.Errorf("%d requests were in flight at once, want at most 2", got)
+	}
+}
+
+func TestRateLimitCancelReleasesSlot(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	client := server.EFDClient(efd.WithRateLimit(efd.RateLimit{RequestsPerSecond: 5, Burst: 1, MaxConcurrent: 1}))
+	query := efd.SearchQuery{PageSize: 100}
+
+	if _, err := client.Search(context.Background(), query); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	// The bucket is empty, so this search takes the only slot and waits for a token until it is cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Search(ctx, query); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want context.DeadlineExceeded", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := client.Search(ctx, query); err != nil {
+		t.Errorf("Search after a cancelled wait: %v", err)
+	}
+
+	if got := server.Requests(searchDataPath); got != 2 {
+		t.Errorf("got %d search requests, want 2", got)
+	}
+}
@@ -0,0 +1,246 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests to efdsearch are retried after transient failures
+// Transport errors, truncated bodies, retryable status codes, and unexpected content in successful responses are all
+// retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request, values below 2 disable retries
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts, a zero value leaves it uncapped
+	MaxBackoff time.Duration
+
+	// Multiplier scales the delay after each attempt, values below 1 are treated as 1
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to this fraction in either direction, between 0 and 1
+	Jitter float64
+
+	// RetryStatus reports whether a response status code should be retried
+	// If nil, DefaultRetryStatus is used
+	RetryStatus func(statusCode int) bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy suitable for the public efdsearch site
+// Four attempts are made with delays starting at one second and doubling up to thirty seconds
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 1 * time.Second,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryStatus:    DefaultRetryStatus,
+	}
+}
+
+// DefaultRetryStatus classifies 429 Too Many Requests and the 5xx gateway and availability errors as retryable
+func DefaultRetryStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// WithRetryPolicy sets the RetryPolicy used for all requests
+// By default requests are not retried
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retry = policy
+	}
+}
+
+// retryableError marks an error returned by a response validator as transient
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e retryableError) Unwrap() error {
+	return e.err
+}
+
+// backoff returns the delay to wait before the provided retry attempt, starting from 1
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		delay = delay * (1 + p.Jitter*(2*rand.Float64()-1))
+	}
+
+	return time.Duration(delay)
+}
+
+// retryStatus reports whether the policy retries the provided status code
+func (p RetryPolicy) retryStatus(statusCode int) bool {
+	if p.RetryStatus == nil {
+		return DefaultRetryStatus(statusCode)
+	}
+
+	return p.RetryStatus(statusCode)
+}
+
+// parseRetryAfter parses a Retry-After header in either delay-seconds or HTTP date form
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// sleepContext waits for the provided duration or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// do sends a request with the provided client, retrying according to the client RetryPolicy
+// The response body is read in full so that truncated bodies can be retried, and is returned buffered in memory
+// validate, if not nil, is called with each 2xx response and its body and can return a retryableError to request
+// another attempt
+// A response with a retryable status code is returned as-is once all attempts have been used
+func (c *EFDClient) do(ctx context.Context, client *http.Client, req *http.Request,
+	validate func(*http.Response, []byte) error) (*http.Response, error) {
+	policy := c.opts.retry
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		var delay time.Duration
+
+		// Error statuses are classified by the policy before validation, so error pages honour RetryStatus and Retry-After
+		resp, body, err := c.doOnce(ctx, client, req)
+		if err == nil && validate != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			err = validate(resp, body)
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		switch {
+		case err != nil:
+			retryable, ok := err.(retryableError)
+			if !ok {
+				return nil, err
+			}
+
+			lastErr = retryable.err
+		case attempt < attempts && policy.retryStatus(resp.StatusCode):
+			lastErr = newHTTPStatusError(resp)
+			delay, _ = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		default:
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			return resp, nil
+		}
+
+		if attempt >= attempts {
+			if attempts > 1 {
+				return nil, fmt.Errorf("giving up after %d attempts: %w", attempts, lastErr)
+			}
+
+			return nil, lastErr
+		}
+
+		if backoff := policy.backoff(attempt); backoff > delay {
+			delay = backoff
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// doOnce makes a single attempt at a request and reads the full response body
+// The attempt waits on the client rate limiter before being sent
+// Transport and body read failures are returned as a retryableError
+func (c *EFDClient) doOnce(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer release()
+
+	attempt := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		attempt.Body = body
+	}
+
+	resp, err := client.Do(attempt)
+	if err != nil {
+		return nil, nil, retryableError{err}
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, retryableError{err}
+	}
+
+	return resp, body, nil
+}
@@ -0,0 +1,90 @@
+package efd_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+const searchDataPath = "/search/report/data/"
+
+// fastRetryPolicy retries quickly so tests are not slowed by backoff
+func fastRetryPolicy() efd.RetryPolicy {
+	return efd.RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func TestRetryTransientSearchFailures(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	server.InjectFailures(searchDataPath, http.StatusServiceUnavailable, http.StatusBadGateway)
+	client := server.EFDClient(efd.WithRetryPolicy(fastRetryPolicy()))
+
+	results, err := client.Search(context.Background(), efd.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if len(results) != len(efdtest.Fixtures()) {
+		t.Errorf("got %d results, want %d", len(results), len(efdtest.Fixtures()))
+	}
+
+	if got := server.Requests(searchDataPath); got != 3 {
+		t.Errorf("got %d search requests, want 3", got)
+	}
+}
+
+func TestRetrySkipsNonRetryableStatus(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	server.InjectFailures(searchDataPath, http.StatusForbidden)
+	client := server.EFDClient(efd.WithRetryPolicy(fastRetryPolicy()))
+
+	if _, err := client.Search(context.Background(), efd.SearchQuery{}); err == nil {
+		t.Fatal("Search succeeded after a 403")
+	}
+
+	if got := server.Requests(searchDataPath); got != 1 {
+		t.Errorf("got %d search requests, want 1", got)
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	server.InjectFailuresRetryAfter(searchDataPath, time.Second, http.StatusTooManyRequests)
+	client := server.EFDClient(efd.WithRetryPolicy(fastRetryPolicy()))
+
+	start := time.Now()
+	if _, err := client.Search(context.Background(), efd.SearchQuery{}); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+}
+
+func TestSearchReturnsHTTPStatusError(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	server.InjectFailures(searchDataPath, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	policy := fastRetryPolicy()
+	policy.MaxAttempts = 2
+	client := server.EFDClient(efd.WithRetryPolicy(policy))
+
+	_, err := client.Search(context.Background(), efd.SearchQuery{})
+
+	var statusErr *efd.HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("got error %v, want an HTTPStatusError with status 503", err)
+	}
+}
@@ -0,0 +1,85 @@
+package efd_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+	"github.com/Individual-1/go-efd/efdtest"
+)
+
+const homePath = "/search/home/"
+
+// authedPTR searches the server for the PTR fixture and fetches it once, so that client has a session
+func authedPTR(t *testing.T, client *efd.EFDClient) efd.SearchResult {
+	t.Helper()
+
+	ctx := context.Background()
+	results, err := client.Search(ctx, efd.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	for _, result := range results {
+		if result.ReportID != efdtest.PTRFixtureID {
+			continue
+		}
+
+		if _, err := client.HandleResultContext(ctx, result); err != nil {
+			t.Fatalf("HandleResultContext: %v", err)
+		}
+
+		return result
+	}
+
+	t.Fatal("PTR fixture not found")
+	return efd.SearchResult{}
+}
+
+func TestReauthAfterSessionExpires(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.EFDClient()
+	result := authedPTR(t, client)
+
+	server.ExpireSessions()
+
+	parsed, err := client.HandleResultContext(ctx, result)
+	if err != nil {
+		t.Fatalf("HandleResultContext: %v", err)
+	}
+
+	if len(parsed.Transactions) != 4 {
+		t.Errorf("got %d transactions after re-authenticating, want 4", len(parsed.Transactions))
+	}
+
+	if got := server.Acceptances(); got != 2 {
+		t.Errorf("disclaimer was accepted %d times, want 2", got)
+	}
+}
+
+func TestReauthFailureIsTyped(t *testing.T) {
+	server := efdtest.NewServer(efdtest.Fixtures()...)
+	defer server.Close()
+
+	client := server.EFDClient()
+	result := authedPTR(t, client)
+
+	server.ExpireSessions()
+	server.InjectFailures(homePath, http.StatusForbidden, http.StatusForbidden, http.StatusForbidden)
+
+	parsed, err := client.HandleResultContext(context.Background(), result)
+
+	var reauthErr *efd.ReauthError
+	if !errors.As(err, &reauthErr) {
+		t.Fatalf("got error %v, want a ReauthError", err)
+	}
+
+	if len(parsed.Transactions) != 0 {
+		t.Errorf("got %d transactions from a failed fetch", len(parsed.Transactions))
+	}
+}
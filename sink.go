@@ -0,0 +1,111 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Sink is a store for named blobs, such as archived responses and downloaded page images
+// Names are slash separated relative paths, and implementations must be safe for concurrent use
+type Sink interface {
+	// Exists reports whether a blob has already been stored under name
+	Exists(ctx context.Context, name string) (bool, error)
+
+	// Put stores body under name, replacing any existing blob
+	Put(ctx context.Context, name string, body []byte) error
+
+	// Get returns the blob stored under name
+	Get(ctx context.Context, name string) ([]byte, error)
+}
+
+// DirSink is a Sink storing each blob as a file under a directory
+type DirSink struct {
+	dir string
+}
+
+// NewDirSink returns a DirSink storing files under dir, which is created as needed
+func NewDirSink(dir string) *DirSink {
+	return &DirSink{dir: dir}
+}
+
+// Exists reports whether a file has already been written for name
+func (s *DirSink) Exists(ctx context.Context, name string) (bool, error) {
+	p, err := s.path(name)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(p)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Put writes body to the file for name
+// The file is written to a temporary name first, so a partially written file is never left under name
+func (s *DirSink) Put(ctx context.Context, name string, body []byte) error {
+	p, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(p), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(p), "."+filepath.Base(p)+".*")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	err = os.Rename(tmp.Name(), p)
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
+
+// Get reads the file for name
+func (s *DirSink) Get(ctx context.Context, name string) ([]byte, error) {
+	p, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadFile(p)
+}
+
+// path converts a sink name into a file path under the sink directory
+// Empty and absolute names, and names which would escape the directory such as ../x, are rejected
+func (s *DirSink) path(name string) (string, error) {
+	clean := path.Clean(name)
+	if name == "" || path.IsAbs(name) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") ||
+		strings.Contains(name, "\\") {
+		return "", fmt.Errorf("efd invalid sink name %q", name)
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,81 @@
+package efd_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Individual-1/go-efd"
+)
+
+func TestDirSinkPutAndGet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dirsink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	sink := efd.NewDirSink(dir)
+
+	if exists, err := sink.Exists(ctx, "reports/a/page.html"); err != nil || exists {
+		t.Fatalf("Exists before Put returned %v, %v", exists, err)
+	}
+
+	for _, body := range []string{"first", "second"} {
+		if err := sink.Put(ctx, "reports/a/page.html", []byte(body)); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	if exists, err := sink.Exists(ctx, "reports/a/page.html"); err != nil || !exists {
+		t.Errorf("Exists after Put returned %v, %v", exists, err)
+	}
+
+	body, err := sink.Get(ctx, "reports/a/page.html")
+	if err != nil || string(body) != "second" {
+		t.Errorf("Get returned %q, %v, want the second body", body, err)
+	}
+
+	files, err := ioutil.ReadDir(filepath.Join(dir, "reports", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 1 || files[0].Name() != "page.html" {
+		for _, f := range files {
+			t.Errorf("unexpected file %s left in the sink", f.Name())
+		}
+	}
+}
+
+func TestDirSinkRejectsEscapingNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dirsink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	sink := efd.NewDirSink(filepath.Join(dir, "sink"))
+
+	for _, name := range []string{"", ".", "..", "../x", "a/../../x", "/etc/passwd", `a\b`} {
+		if err := sink.Put(ctx, name, []byte("x")); err == nil {
+			t.Errorf("Put accepted %q", name)
+		}
+
+		if _, err := sink.Exists(ctx, name); err == nil {
+			t.Errorf("Exists accepted %q", name)
+		}
+
+		if _, err := sink.Get(ctx, name); err == nil {
+			t.Errorf("Get accepted %q", name)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "x")); !os.IsNotExist(err) {
+		t.Errorf("a file was written outside the sink")
+	}
+}
@@ -0,0 +1,154 @@
+// Package efd implements helper functions for interacting with the efd search and managing the results
+package efd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// transactionHeaders maps normalized transaction table header labels to the cell type of their column
+// Labels are lowercased with whitespace collapsed, see normalizeHeader
+var transactionHeaders = map[string]cellType{
+	"":                 ignoreCell,
+	"#":                trNumCell,
+	"transaction date": trDateCell,
+	"date":             trDateCell,
+	"owner":            ownerCell,
+	"ticker":           tickerCell,
+	"asset name":       assetNameCell,
+	"asset":            assetNameCell,
+	"asset type":       assetTypeCell,
+	"type":             trTypeCell,
+	"transaction type": trTypeCell,
+	"amount":           amountCell,
+	"comment":          commentCell,
+	"comments":         commentCell,
+}
+
+// requiredTransactionCells are the columns a transaction table must have for its rows to be usable
+var requiredTransactionCells = []cellType{trDateCell, assetNameCell, trTypeCell, amountCell}
+
+// normalizeHeader lowercases a header label and collapses its whitespace
+func normalizeHeader(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// tableColumns reads the <thead> labels of a table and maps them to cell types using the provided headers
+// Unrecognised labels are mapped to ignoreCell and returned so they can be reported
+func (c *EFDClient) tableColumns(table *goquery.Selection, headers map[string]cellType) ([]cellType, []string) {
+	var columns []cellType
+	var unknown []string
+
+	table.Find("thead tr").First().ChildrenFiltered("th, td").Each(func(i int, th *goquery.Selection) {
+		label := strings.Join(strings.Fields(th.Text()), " ")
+
+		ct, ok := headers[normalizeHeader(label)]
+		if !ok {
+			ct = ignoreCell
+			unknown = append(unknown, label)
+		}
+
+		columns = append(columns, ct)
+	})
+
+	return columns, unknown
+}
+
+// parseTransactionTable parses the rows of a transaction table using its header labels to identify each column,
+// so columns can be reordered or added without breaking parsing
+// Rows which do not match the header or fail to parse are dropped and recorded in diag, along with any header
+// labels which were not recognised
+func (c *EFDClient) parseTransactionTable(ctx context.Context, section string, table *goquery.Selection,
+	diag *ParseReport) ([]Transaction, error) {
+	var transactions []Transaction
+
+	columns, unknown := c.tableColumns(table, transactionHeaders)
+	for _, label := range unknown {
+		diag.UnknownColumns = append(diag.UnknownColumns, fmt.Sprintf("%s: %s", section, label))
+	}
+
+	for _, required := range requiredTransactionCells {
+		if !containsCellType(columns, required) {
+			return nil, &ParseError{Section: section, Row: -1,
+				Err: fmt.Errorf("transaction table is missing a column for %s", required)}
+		}
+	}
+
+	trs := table.Find("tbody tr")
+	transactions = make([]Transaction, 0, trs.Length())
+	trs.EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		var transaction Transaction
+		tds := s.ChildrenFiltered("td")
+		if tds.Length() != len(columns) {
+			diag.skipSelection(section, i, s, fmt.Sprintf("row has %d cells, expected %d", tds.Length(), len(columns)))
+			return true
+		}
+
+		valid := true
+		tds.EachWithBreak(func(j int, t *goquery.Selection) bool {
+			valid = c.handleTransactionCell(&transaction, t, columns[j])
+			if !valid {
+				diag.skipSelection(section, i, s, fmt.Sprintf("could not parse %s", columns[j]))
+			}
+
+			return valid
+		})
+
+		if valid {
+			c.handleTransactionCell(&transaction, nil, validCell)
+			transactions = append(transactions, transaction)
+		}
+
+		return true
+	})
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// containsCellType reports whether ct is present in cts
+func containsCellType(cts []cellType, ct cellType) bool {
+	for _, v := range cts {
+		if v == ct {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the column name for a cell type
+func (ct cellType) String() string {
+	switch ct {
+	case trNumCell:
+		return "transaction number"
+	case trDateCell:
+		return "transaction date"
+	case ownerCell:
+		return "owner"
+	case tickerCell:
+		return "ticker"
+	case assetNameCell:
+		return "asset name"
+	case assetTypeCell:
+		return "asset type"
+	case trTypeCell:
+		return "transaction type"
+	case amountCell:
+		return "amount"
+	case commentCell:
+		return "comment"
+	}
+
+	return "ignored"
+}
@@ -0,0 +1,56 @@
+package efd_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Individual-1/go-efd"
+)
+
+// transactionTable wraps a transaction table in the markup of a PTR
+func transactionTable(header string, rows ...string) string {
+	return `<div class="table-responsive"><table class="table"><thead><tr>` + header + `</tr></thead><tbody>` +
+		strings.Join(rows, "") + `</tbody></table></div>`
+}
+
+func TestTransactionTableReorderedColumns(t *testing.T) {
+	page := transactionTable(
+		`<th>Amount</th><th>Asset Name</th><th>Notes</th><th>Type</th><th>Transaction Date</th><th>Ticker</th>`,
+		`<tr><td>$1,001 - $15,000</td><td>Apple Inc.</td><td>x</td><td>Purchase</td><td>03/10/2020</td>`+
+			`<td><a href="https://finance.yahoo.com/quote/AAPL">AAPL</a></td></tr>`,
+	)
+
+	parsed, err := efd.ParseDocument(strings.NewReader(page), efd.PTRFormat, efd.ParseOptions{})
+	if err != nil {
+		t.Fatalf("ParseDocument: %v", err)
+	}
+
+	if len(parsed.Transactions) != 1 {
+		t.Fatalf("got %d transactions, want 1", len(parsed.Transactions))
+	}
+
+	got := parsed.Transactions[0]
+	if got.Ticker != "AAPL" || got.AssetName != "Apple Inc." || got.Kind != efd.PurchaseTransaction ||
+		got.AmountBand != efd.Amount1KTo15K || !got.Date.Equal(time.Date(2020, time.March, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected transaction %+v", got)
+	}
+
+	if want := []string{"Transactions: Notes"}; !reflect.DeepEqual(parsed.Diagnostics.UnknownColumns, want) {
+		t.Errorf("got unknown columns %v, want %v", parsed.Diagnostics.UnknownColumns, want)
+	}
+}
+
+func TestTransactionTableMissingColumn(t *testing.T) {
+	page := transactionTable(`<th>Transaction Date</th><th>Asset Name</th><th>Type</th>`,
+		`<tr><td>03/10/2020</td><td>Apple Inc.</td><td>Purchase</td></tr>`)
+
+	_, err := efd.ParseDocument(strings.NewReader(page), efd.PTRFormat, efd.ParseOptions{})
+
+	var parseErr *efd.ParseError
+	if !errors.As(err, &parseErr) || !strings.Contains(parseErr.Error(), "amount") {
+		t.Errorf("got error %v, want a ParseError for the missing amount column", err)
+	}
+}
@@ -105,12 +105,14 @@ type SearchResults struct {
 // SearchResult is a struct matching an individual result array from efdsearch
 // Each result is an array of 5 strings containing
 // First Name, Last Name, Full Name, File Link, and Date Submitted
+// Title holds the covered period and amendment number parsed from ReportName
 type SearchResult struct {
 	FirstName     string
 	LastName      string
 	FullName      string
 	FileURL       *url.URL
 	ReportName    string
+	Title         ReportTitle
 	ReportFormat  ReportFormat
 	ReportID      string
 	DateSubmitted time.Time
@@ -131,26 +133,164 @@ func (s SearchResult) GenAnnualSearchResultPath() string {
 }
 
 // ParsedReport is a small wrapper around possible report outputs
+// Diagnostics lists any rows which were skipped, along with table headers which were not recognised and whose
+// columns were ignored, which usually means efdsearch has changed its layout
+// Annual is only populated for digital Annual reports, whose Part 4a and 4b transactions are also in Transactions
+// Extension is only populated for due date extension notices
 type ParsedReport struct {
 	ReportFormat ReportFormat
 	Transactions []Transaction
+	Annual       AnnualDisclosure
+	Extension    ExtensionNotice
 	Pages        PaperReport
+	Diagnostics  ParseReport
 }
 
 // Transaction is a struct matching the output of a digital PTR report
+// AmountMin and AmountMax are the parsed bounds of Amount in cents, and are only set if AmountValid is true
+// AmountMax is 0 when AmountOpenEnded is set, as for "Over $50,000,000"
+// Kind, OwnerKind, and AssetKind are parsed from Type, Owner, and AssetType, which keep the raw text
+// Option is only set for options, with its terms parsed from AssetName and Comment
 type Transaction struct {
-	Date      time.Time `json:"date"`
-	Owner     string    `json:"owner,omitempty"`
-	Ticker    string    `json:"ticker,omitempty"`
-	AssetName string    `json:"assetname,omitempty"`
-	AssetType string    `json:"assettype,omitempty"`
-	Type      string    `json:"type,omitempty"`
-	Amount    string    `json:"amount,omitempty"`
-	Comment   string    `json:"comment,omitempty"`
-	Valid     bool      `json:"-"`
+	Date            time.Time       `json:"date"`
+	Owner           string          `json:"owner,omitempty"`
+	OwnerKind       OwnerKind       `json:"ownerkind"`
+	Ticker          string          `json:"ticker,omitempty"`
+	AssetName       string          `json:"assetname,omitempty"`
+	AssetType       string          `json:"assettype,omitempty"`
+	AssetKind       AssetKind       `json:"assetkind"`
+	Option          *OptionDetails  `json:"option,omitempty"`
+	Type            string          `json:"type,omitempty"`
+	Kind            TransactionKind `json:"kind"`
+	Amount          string          `json:"amount,omitempty"`
+	AmountMin       int64           `json:"amountmin"`
+	AmountMax       int64           `json:"amountmax"`
+	AmountOpenEnded bool            `json:"amountopenended,omitempty"`
+	AmountBand      AmountBand      `json:"amountband"`
+	AmountValid     bool            `json:"amountvalid"`
+	Comment         string          `json:"comment,omitempty"`
+	Valid           bool            `json:"-"`
+}
+
+// AnnualDisclosure is a struct containing each part of a digital Annual report
+type AnnualDisclosure struct {
+	CharitableContributions []CharitableContribution `json:"charitablecontributions,omitempty"`
+	EarnedIncome            []EarnedIncome           `json:"earnedincome,omitempty"`
+	Assets                  []Asset                  `json:"assets,omitempty"`
+	PTRTransactions         []Transaction            `json:"ptrtransactions,omitempty"`
+	Transactions            []Transaction            `json:"transactions,omitempty"`
+	Gifts                   []Gift                   `json:"gifts,omitempty"`
+	Travel                  []TravelReimbursement    `json:"travel,omitempty"`
+	Liabilities             []Liability              `json:"liabilities,omitempty"`
+	Positions               []Position               `json:"positions,omitempty"`
+	Agreements              []Agreement              `json:"agreements,omitempty"`
+	Compensation            []Compensation           `json:"compensation,omitempty"`
+}
+
+// CharitableContribution is a row of Part 1, honoraria paid to charity in lieu of the filer
+type CharitableContribution struct {
+	Date     time.Time `json:"date"`
+	Activity string    `json:"activity,omitempty"`
+	Amount   string    `json:"amount,omitempty"`
+	PaidBy   string    `json:"paidby,omitempty"`
+	PaidTo   string    `json:"paidto,omitempty"`
+}
+
+// EarnedIncome is a row of Part 2, earned and non-investment income
+type EarnedIncome struct {
+	Recipient string `json:"recipient,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Payer     string `json:"payer,omitempty"`
+	Amount    string `json:"amount,omitempty"`
+}
+
+// Asset is a row of Part 3, assets and unearned income
+// Description holds any additional details listed under the asset name
+type Asset struct {
+	Name        string `json:"name,omitempty"`
+	Ticker      string `json:"ticker,omitempty"`
+	Description string `json:"description,omitempty"`
+	AssetType   string `json:"assettype,omitempty"`
+	Owner       string `json:"owner,omitempty"`
+	Value       string `json:"value,omitempty"`
+	IncomeType  string `json:"incometype,omitempty"`
+	Income      string `json:"income,omitempty"`
+}
+
+// Gift is a row of Part 5, gifts received
+type Gift struct {
+	Date        time.Time `json:"date"`
+	Recipient   string    `json:"recipient,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Value       string    `json:"value,omitempty"`
+	Source      string    `json:"source,omitempty"`
+}
+
+// TravelReimbursement is a row of Part 6, travel paid for or reimbursed by others
+type TravelReimbursement struct {
+	StartDate    time.Time `json:"startdate"`
+	EndDate      time.Time `json:"enddate"`
+	Travelers    string    `json:"travelers,omitempty"`
+	Itinerary    string    `json:"itinerary,omitempty"`
+	Purpose      string    `json:"purpose,omitempty"`
+	ReimbursedBy string    `json:"reimbursedby,omitempty"`
+}
+
+// Liability is a row of Part 7, liabilities owed
+type Liability struct {
+	YearIncurred string `json:"yearincurred,omitempty"`
+	Debtor       string `json:"debtor,omitempty"`
+	Type         string `json:"type,omitempty"`
+	Points       string `json:"points,omitempty"`
+	Term         string `json:"term,omitempty"`
+	Rate         string `json:"rate,omitempty"`
+	Amount       string `json:"amount,omitempty"`
+	Creditor     string `json:"creditor,omitempty"`
+}
+
+// Position is a row of Part 8, positions held outside the government
+type Position struct {
+	Dates      string `json:"dates,omitempty"`
+	Position   string `json:"position,omitempty"`
+	Entity     string `json:"entity,omitempty"`
+	EntityType string `json:"entitytype,omitempty"`
+	Comment    string `json:"comment,omitempty"`
+}
+
+// Agreement is a row of Part 9, agreements or arrangements such as pensions and leaves of absence
+// Date is kept as text as it is often only a month and year
+type Agreement struct {
+	Date    string `json:"date,omitempty"`
+	Parties string `json:"parties,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Terms   string `json:"terms,omitempty"`
+}
+
+// Compensation is a row of Part 10, compensation in excess of $5,000 paid by one source
+type Compensation struct {
+	Source string `json:"source,omitempty"`
+	Duties string `json:"duties,omitempty"`
+}
+
+// ExtensionNotice is a struct containing the details of a due date extension notice
+// ExtensionLength is the length as written on the notice, while ExtensionDays is the length in days
+type ExtensionNotice struct {
+	ReportExtended  string    `json:"reportextended,omitempty"`
+	OriginalDueDate time.Time `json:"originalduedate"`
+	NewDueDate      time.Time `json:"newduedate"`
+	ExtensionLength string    `json:"extensionlength,omitempty"`
+	ExtensionDays   int       `json:"extensiondays"`
+	Filer           string    `json:"filer,omitempty"`
+	FilerType       string    `json:"filertype,omitempty"`
+	State           string    `json:"state,omitempty"`
 }
 
 // PaperReport is a struct containing data about filed paper reports
+// PageURLs are in page order, so page n of the report is PageURLs[n-1], with a nil entry for any page image which
+// had no usable URL so that later pages keep their number
+// PageHashes are the sha256 hashes of each page image, empty for pages with no URL, and are only set by HashPaperPages
 type PaperReport struct {
-	PageURLs []*url.URL
+	ReportID   string
+	PageURLs   []*url.URL
+	PageHashes []string
 }